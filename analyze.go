@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"slices"
+	"strings"
+	"task-generator/lib"
+	"task-generator/lib/analysis"
+)
+
+// analyze runs the schedulability analysis of the task sets in the output folder of a config file, e.g.,
+// "generate analyze -config config.yaml -tests rta,qpa -priority DM"
+func analyze(args []string) {
+	flags := flag.NewFlagSet("analyze", flag.ExitOnError)
+	configFile := flags.String("config", "config.yaml", "path to the YAML config file")
+	tests := flags.String("tests", "", "comma-separated schedulability tests: \"rta\", \"qpa\", \"gfb\", \"bcl\", "+
+		"\"baruah\", \"bc-rta\", \"graham\", \"melani\", \"federated\" "+
+		"(default: \"qpa\" for the EDF priority assignment of the config file, \"rta\" otherwise)")
+	priority := flags.String("priority", "", "priority policy of the response-time analyses: \"RM\", \"DM\", "+
+		"\"DkC\", \"OPA\", or \"explicit\" (default: the priority assignment of the config file, or RM)")
+	cores := flags.Int("cores", 0, "number of cores of the global and the DAG tests "+
+		"(default: the number of cores of each task set)")
+	flags.Parse(args)
+	config := readConfig(*configFile)
+
+	testList := strings.Split(*tests, ",")
+	if *tests == "" {
+		testList = []string{"rta"}
+		if config.PriorityAssignment == "EDF" {
+			testList = []string{"qpa"}
+		}
+	}
+
+	policy := *priority
+	if policy == "" {
+		policy = config.PriorityAssignment
+	}
+	if policy == "" {
+		// like the generation, RM is the default priority assignment
+		policy = analysis.RM
+	}
+	for _, test := range testList {
+		fixedPriority := test == "rta" || test == "bc-rta" || test == "melani"
+		if fixedPriority && !slices.Contains(analysis.PriorityPolicies, policy) {
+			logger.LogFatal("Invalid priority policy for the response-time analysis: " + policy)
+		}
+		if test == "bc-rta" && policy == analysis.OPA {
+			logger.LogFatal("The OPA priorities are only defined for the partitioned response-time analysis (rta)")
+		}
+	}
+
+	if err := lib.AnalyzeTaskSets(config.Path, config.OutputFormat, testList, policy, *cores); err != nil {
+		logger.LogFatal("Error analyzing task sets: " + err.Error())
+	}
+	logger.LogInfo("Analysis reports are written next to the task sets in " + config.Path)
+}
@@ -8,49 +8,81 @@ import (
 	"os"
 	"task-generator/lib"
 	"task-generator/lib/common"
+	"time"
 )
 
-// Config represents the structure of the YAML configuration file
+// Config represents the structure of the YAML configuration file.
+// The sweep parameters can be given as a single value, a list, or a {from, to, step} range.
 type Config struct {
-	Path               string    `yaml:"path"`
-	OutputFormat       string    `yaml:"output_format"`
-	NumCores           int       `yaml:"number_of_cores"`
-	UtilDistribution   string    `yaml:"utilization_distribution"`
-	UtilBounds         []float64 `yaml:"utilization_bound"`
-	PeriodDistribution string    `yaml:"period_distribution"`
-	PeriodRange        []int     `yaml:"period_range"`
-	Periods            []int     `yaml:"periods"`
-	NumSets            int       `yaml:"num_sets"`
-	Tasks              int       `yaml:"tasks"`
-	Utilization        float64   `yaml:"utilization"`
-	ExecVariation      float64   `yaml:"exec_variation"`
-	Jitter             float64   `yaml:"jitter"`
-	ConstantJitter     bool      `yaml:"constant_jitter"`
-	MaxJobs            int       `yaml:"max_jobs"`
-	MappingHeuristic   int       `yaml:"mapping_heuristic"`
-	GenerateDAGs       bool      `yaml:"generate_dags"`
-	MakeDotFile        bool      `yaml:"generate_dot"`
-	DAGType            string    `yaml:"dag_type"`
-	ForkProb           float64   `yaml:"fork_probability"`
-	EdgeProb           float64   `yaml:"edge_probability"`
-	MaxBranch          int       `yaml:"max_branches"`
-	MaxVertices        int       `yaml:"max_vertices"`
-	NumRoots           int       `yaml:"num_roots"`
-	MaxDepth           int       `yaml:"max_depth"`
-	GenerateJobs       bool      `yaml:"generate_job_sets"`
-	PriorityAssignment string    `yaml:"priority_assignment"`
-	RunParallel        bool      `yaml:"run_parallel"`
-	Verbose            int       `yaml:"verbose"`
+	Path               string     `yaml:"path"`
+	OutputFormat       string     `yaml:"output_format"`
+	ExportFormats      []string   `yaml:"export_formats"`
+	SimSoScheduler     string     `yaml:"simso_scheduler"`
+	NumCores           IntSweep   `yaml:"number_of_cores"`
+	UtilDistribution   string     `yaml:"utilization_distribution"`
+	UtilBounds         []float64  `yaml:"utilization_bound"`
+	UtilLowerBounds    []float64  `yaml:"utilization_lower_bounds"`
+	UtilUpperBounds    []float64  `yaml:"utilization_upper_bounds"`
+	PeriodDistribution string     `yaml:"period_distribution"`
+	PeriodRange        []int      `yaml:"period_range"`
+	Periods            []int      `yaml:"periods"`
+	Harmonic           Harmonic   `yaml:"harmonic"`
+	PeriodFactors      [][]int    `yaml:"period_factors"`
+	NumSets            int        `yaml:"num_sets"`
+	Tasks              IntSweep   `yaml:"tasks"`
+	Utilization        FloatSweep `yaml:"utilization"`
+	ExecVariation      float64    `yaml:"exec_variation"`
+	DeadlineModel      string     `yaml:"deadline_model"`
+	DeadlineFactor     float64    `yaml:"deadline_factor"`
+	OffsetModel        string     `yaml:"offset_model"`
+	OffsetGranularity  int        `yaml:"offset_granularity"`
+	ArrivalModel       string     `yaml:"arrival_model"`
+	ArrivalDelay       float64    `yaml:"arrival_delay"`
+	BurstLength        float64    `yaml:"burst_length"`
+	Horizon            int        `yaml:"horizon"`
+	HIFraction         float64    `yaml:"hi_fraction"`
+	HIWCETFactor       float64    `yaml:"hi_wcet_factor"`
+	Jitter             FloatSweep `yaml:"jitter"`
+	ConstantJitter     bool       `yaml:"constant_jitter"`
+	MaxJobs            int        `yaml:"max_jobs"`
+	MappingHeuristic   int        `yaml:"mapping_heuristic"`
+	GenerateDAGs       bool       `yaml:"generate_dags"`
+	MakeDotFile        bool       `yaml:"generate_dot"`
+	DAGType            string     `yaml:"dag_type"`
+	ForkProb           FloatSweep `yaml:"fork_probability"`
+	EdgeProb           FloatSweep `yaml:"edge_probability"`
+	MaxBranch          IntSweep   `yaml:"max_branches"`
+	MaxVertices        IntSweep   `yaml:"max_vertices"`
+	NumRoots           IntSweep   `yaml:"num_roots"`
+	MaxDepth           IntSweep   `yaml:"max_depth"`
+	GenerateJobs       bool       `yaml:"generate_job_sets"`
+	PriorityAssignment string     `yaml:"priority_assignment"`
+	Filter             Filter     `yaml:"filter"`
+	RunParallel        bool       `yaml:"run_parallel"`
+	// Seed is nil without a seed, so that a seed of 0 can be given
+	Seed    *int64 `yaml:"seed"`
+	Verbose int    `yaml:"verbose"`
 }
 
-var logger *common.VerboseLogger
+// Filter keeps only the task sets with the given verdict of a schedulability test
+type Filter struct {
+	Test        string `yaml:"test"`
+	Keep        string `yaml:"keep"`
+	Priority    string `yaml:"priority"`
+	MaxAttempts int    `yaml:"max_attempts"`
+}
 
-func main() {
-	//	first we need to read the config file
-	var configFile string
-	flag.StringVar(&configFile, "config", "config.yaml", "path to the YAML config file")
-	flag.Parse()
+// Harmonic sets the harmonic period distributions
+type Harmonic struct {
+	Base        int   `yaml:"base"`
+	Multipliers []int `yaml:"multipliers"`
+	Chains      int   `yaml:"chains"`
+}
 
+var logger *common.VerboseLogger
+
+// readConfig reads the config file and sets the logger with its verbose level
+func readConfig(configFile string) Config {
 	configData, err := ioutil.ReadFile(configFile)
 	if err != nil {
 		fmt.Printf("Error reading config file: %v\n", err)
@@ -72,88 +104,178 @@ func main() {
 		fmt.Println("Error: Invalid verbose level")
 		os.Exit(1)
 	}
+	return config
+}
 
-	if config.OutputFormat != "csv" && config.OutputFormat != "yaml" {
-		logger.LogFatal("Invalid output format")
+func main() {
+	// the "analyze" command analyzes the task sets that are already generated
+	if len(os.Args) > 1 && os.Args[1] == "analyze" {
+		analyze(os.Args[2:])
+		return
 	}
-
-	// Print a warning that the automotive method is not consider number of tasks
-	if config.PeriodDistribution == "automotive" && config.UtilDistribution == "automotive" {
-		logger.LogWarning("The automotive method does not consider the number of tasks")
+	// the "simulate" command simulates the job sets that are already generated
+	if len(os.Args) > 1 && os.Args[1] == "simulate" {
+		simulate(os.Args[2:])
+		return
 	}
-	// Print a fatal error if the period distribution is automotive and the utilization distribution is not automotive
-	if config.UtilDistribution == "automotive" && config.PeriodDistribution != "automotive" {
-		logger.LogFatal("The utilization distribution is automotive but the period distribution is not automotive")
+	// the "import" command imports a model of another tool as a task set
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		importModel(os.Args[2:])
+		return
 	}
 
-	// Print a fatal error if the period distribution is not fixed sum and there is a utilization bound
-	if config.UtilDistribution != "rand-fixed-sum" && len(config.UtilBounds) > 0 {
-		logger.LogFatal("The utilization bounds are only valid for rand-fixed-sum utilization distribution")
-	} else if config.UtilDistribution == "rand-fixed-sum" && len(config.UtilBounds) == 0 {
-		// set default utilization bounds
-		config.UtilBounds = []float64{0.0, 1.0}
+	//	first we need to read the config file
+	var configFile string
+	flag.StringVar(&configFile, "config", "config.yaml", "path to the YAML config file")
+	flag.Parse()
+	config := readConfig(configFile)
+
+	// without a seed, we take a random one and report it, so the run can be repeated
+	if config.Seed == nil {
+		seed := time.Now().UnixNano()
+		config.Seed = &seed
+		logger.LogInfo(fmt.Sprintf("No seed is given, using seed %d", seed))
+	}
+
+	// a sweep generates the task sets of each point in the cartesian product of the swept parameters
+	points := config.Points()
+	if len(points) > 1 {
+		logger.LogInfo(fmt.Sprintf("Sweeping over %d points", len(points)))
 	}
+	// with swept DAG parameters, each DAG point has its own output folder
+	dagSweep := config.IsDAGSweep()
 
 	//	then we need to create the task sets
-	// 	we can run the task generation in parallel if the config file specifies it
-	if config.RunParallel {
-		lib.CreateTaskSetsParallel(config.Path, config.NumCores, config.NumSets, config.Tasks,
-			config.Utilization, config.UtilDistribution, config.UtilBounds, config.PeriodDistribution, config.PeriodRange,
-			config.Periods, config.ExecVariation, config.Jitter, config.ConstantJitter, config.MaxJobs,
-			config.MappingHeuristic, config.OutputFormat, logger)
-	} else {
-		lib.CreateTaskSets(config.Path, config.NumCores, config.NumSets, config.Tasks,
-			config.Utilization, config.UtilDistribution, config.UtilBounds, config.PeriodDistribution, config.PeriodRange,
-			config.Periods, config.ExecVariation, config.Jitter, config.ConstantJitter, config.MaxJobs,
-			config.MappingHeuristic, config.OutputFormat, logger)
+	// 	we keep the generator of the first point of each output folder to generate the DAGs and job sets of that folder
+	var roots []string
+	generators := make(map[string]*lib.Generator)
+	for _, point := range points {
+		generator, err := newGenerator(point)
+		if err != nil {
+			logger.LogFatal(err.Error())
+		}
+		root := point.Root(dagSweep)
+		if err := generator.WriteTaskSets(root, point.NumSets); err != nil {
+			logger.LogFatal("Error creating task sets: " + err.Error())
+		}
+		if _, ok := generators[root]; !ok {
+			generators[root] = generator
+			roots = append(roots, root)
+		}
 	}
 
 	// then we need to generate the DAGs
 	if config.GenerateDAGs {
-		if config.RunParallel {
-			if config.DAGType == "fork-join" {
-				lib.GenerateDAGSetsParallel(config.Path, config.ForkProb, config.EdgeProb, config.MaxBranch,
-					config.MaxVertices, config.MaxDepth, config.MakeDotFile, config.OutputFormat)
-			} else if config.DAGType == "random" {
-				lib.GenerateRandomDAGsParallel(config.Path, config.NumRoots, config.MaxBranch, config.MaxDepth,
-					config.MakeDotFile, config.OutputFormat)
-			} else if config.DAGType == "chain" {
-				lib.GenerateTaskChainsParallel(config.Path, config.MakeDotFile, config.OutputFormat)
-			} else {
-				logger.LogFatal("Invalid DAG type")
-			}
-		} else {
-			if config.DAGType == "fork-join" {
-				lib.GenerateDAGSets(config.Path, config.ForkProb, config.EdgeProb, config.MaxBranch, config.MaxVertices,
-					config.MaxDepth, config.MakeDotFile, config.OutputFormat)
-			} else if config.DAGType == "random" {
-				lib.GenerateRandomDAGs(config.Path, config.NumRoots, config.MaxBranch, config.MaxDepth,
-					config.MakeDotFile, config.OutputFormat)
-			} else if config.DAGType == "chain" {
-				lib.GenerateTaskChains(config.Path, config.MakeDotFile, config.OutputFormat)
-			} else {
-				logger.LogFatal("Invalid DAG type")
+		for _, root := range roots {
+			if err := generators[root].WriteDAGSets(root, config.MakeDotFile); err != nil {
+				logger.LogFatal("Error generating DAGs: " + err.Error())
 			}
 		}
 	}
 
 	//	then we need to generate the job sets
 	if config.GenerateJobs {
-		// first change the priority assignment to an integer
-		var priorityAssignment int
-		switch config.PriorityAssignment {
-		case "RM":
-			priorityAssignment = lib.RM
-		case "DM":
-			priorityAssignment = lib.DM
-		case "EDF":
-			priorityAssignment = lib.EDF
+		for _, root := range roots {
+			if err := generators[root].WriteJobSets(root); err != nil {
+				logger.LogFatal("Error generating job sets: " + err.Error())
+			}
 		}
-		if config.RunParallel {
-			lib.GenerateJobSetsParallel(config.Path, priorityAssignment, config.OutputFormat)
-		} else {
-			lib.GenerateJobSets(config.Path, priorityAssignment, config.OutputFormat)
+	}
+
+	// finally, the task sets are exported to the other tools
+	if len(config.ExportFormats) > 0 {
+		for _, root := range roots {
+			if err := generators[root].WriteExports(root); err != nil {
+				logger.LogFatal("Error exporting task sets: " + err.Error())
+			}
 		}
 	}
 
 }
+
+// newGenerator creates the generator of a sweep point
+func newGenerator(point Config) (*lib.Generator, error) {
+	// the provenance of the point is recorded in the manifest of each task set, and its hash leaves out where and how
+	// the sets are written
+	provenance, err := lib.NewProvenance(point, "path", "output_format", "export_formats", "simso_scheduler",
+		"run_parallel", "verbose")
+	if err != nil {
+		return nil, fmt.Errorf("error creating the provenance of the run: %v", err)
+	}
+
+	// change the priority assignment to an integer
+	var priorityAssignment int
+	switch point.PriorityAssignment {
+	case "RM", "":
+		priorityAssignment = lib.RM
+	case "DM":
+		priorityAssignment = lib.DM
+	case "EDF":
+		priorityAssignment = lib.EDF
+	case "DkC":
+		priorityAssignment = lib.DkC
+	case "OPA":
+		priorityAssignment = lib.OPA
+	case "explicit":
+		priorityAssignment = lib.Explicit
+	default:
+		return nil, fmt.Errorf("invalid priority assignment: %s", point.PriorityAssignment)
+	}
+
+	options := []lib.Option{
+		lib.WithSeed(*point.Seed),
+		lib.WithCores(point.NumCores[0]),
+		lib.WithTasks(point.Tasks[0]),
+		lib.WithUtilization(point.Utilization[0]),
+		lib.WithUtilizationDistribution(point.UtilDistribution, point.UtilBounds...),
+		lib.WithUtilizationTaskBounds(point.UtilLowerBounds, point.UtilUpperBounds),
+		lib.WithPeriodDistribution(point.PeriodDistribution, point.PeriodRange, point.Periods),
+		lib.WithHarmonicPeriods(point.Harmonic.Base, point.Harmonic.Multipliers, point.Harmonic.Chains),
+		lib.WithPeriodFactors(point.PeriodFactors),
+		lib.WithExecVariation(point.ExecVariation),
+		lib.WithDeadlines(point.DeadlineModel, point.DeadlineFactor),
+		lib.WithOffsets(point.OffsetModel, point.OffsetGranularity),
+		lib.WithArrivals(point.ArrivalModel, point.ArrivalDelay, point.BurstLength),
+		lib.WithHorizon(point.Horizon),
+		lib.WithMixedCriticality(point.HIFraction, point.HIWCETFactor),
+		lib.WithJitter(point.Jitter[0], point.ConstantJitter),
+		lib.WithMaxJobs(point.MaxJobs),
+		lib.WithMappingHeuristic(point.MappingHeuristic),
+		lib.WithPriorityAssignment(priorityAssignment),
+		lib.WithOutputFormat(point.OutputFormat),
+		lib.WithExports(point.ExportFormats),
+		lib.WithSimSoScheduler(point.SimSoScheduler),
+		lib.WithParallel(point.RunParallel),
+		lib.WithProgressBar(point.Verbose == common.VerboseLevelNone),
+		lib.WithProvenance(provenance),
+		lib.WithLogger(logger),
+	}
+	if point.Filter.Test != "" {
+		if point.Filter.Keep != "" && point.Filter.Keep != "schedulable" && point.Filter.Keep != "unschedulable" {
+			return nil, fmt.Errorf("invalid verdict of the filter: %s", point.Filter.Keep)
+		}
+		policy := point.Filter.Priority
+		if policy == "" {
+			policy = point.PriorityAssignment
+		}
+		if policy == "" {
+			policy = "RM"
+		}
+		options = append(options, lib.WithFilter(point.Filter.Test, policy, point.Filter.Keep != "unschedulable",
+			point.Filter.MaxAttempts))
+	}
+	if point.GenerateDAGs {
+		switch point.DAGType {
+		case "fork-join":
+			options = append(options, lib.WithForkJoinDAG(point.ForkProb[0], point.EdgeProb[0], point.MaxBranch[0],
+				point.MaxVertices[0], point.MaxDepth[0]))
+		case "random":
+			options = append(options, lib.WithRandomDAG(point.NumRoots[0], point.MaxBranch[0], point.MaxDepth[0]))
+		case "chain":
+			options = append(options, lib.WithTaskChain())
+		default:
+			return nil, fmt.Errorf("invalid DAG type: %s", point.DAGType)
+		}
+	}
+	return lib.NewGenerator(options...)
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"flag"
+	"task-generator/lib"
+	"task-generator/lib/common"
+)
+
+// importModel imports the tasks of a model of another tool as a task set, e.g.,
+// "generate import -amalthea model.amxmi -output tasksets/model.csv"
+func importModel(args []string) {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	amalthea := flags.String("amalthea", "", "path to the Amalthea (APP4MC) model")
+	output := flags.String("output", "", "path to the task set, whose extension is its format: \".csv\", "+
+		"\".yaml\", or \".json\"")
+	flags.Parse(args)
+	logger = common.NewVerboseLogger("", common.VerboseLevelInfo)
+	if *amalthea == "" || *output == "" {
+		logger.LogFatal("The import command needs -amalthea and -output")
+	}
+	if err := lib.ImportAmalthea(*amalthea, *output); err != nil {
+		logger.LogFatal("Error importing the Amalthea model: " + err.Error())
+	}
+	logger.LogInfo("The task set is written to " + *output)
+}
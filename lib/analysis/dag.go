@@ -0,0 +1,351 @@
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"task-generator/lib/common"
+)
+
+//	The DAG tests analyze the vertex sets of the DAG generators as sporadic DAG tasks on m identical cores. A DAG task
+//	is a connected component of the precedence graph, whose vertices are released together. In the fork-join DAGs
+//	each task is a DAG, while in the random DAGs and the chains the vertices are the tasks of the task set; then the
+//	DAG is released with the shortest period and the shortest deadline of its vertices, which is pessimistic.
+//	The release jitter is handled like in the global tests.
+
+// DAGTask is a DAG task of a vertex set
+type DAGTask struct {
+	// TaskID is the smallest task ID of the vertices of the DAG
+	TaskID       int
+	Vertices     int
+	Jitter       int
+	Period       int
+	Deadline     int
+	Volume       int
+	CriticalPath int
+	taskIDs      []int
+}
+
+// DAGResult is the result of the analysis of a DAG task
+type DAGResult struct {
+	TaskID       int  `yaml:"task_id"`
+	Vertices     int  `yaml:"vertices"`
+	Period       int  `yaml:"period"`
+	Deadline     int  `yaml:"deadline"`
+	Volume       int  `yaml:"volume"`
+	CriticalPath int  `yaml:"critical_path"`
+	GrahamBound  int  `yaml:"graham_bound"`
+	Priority     int  `yaml:"priority,omitempty"`
+	WCRT         int  `yaml:"wcrt,omitempty"`
+	Cores        int  `yaml:"cores,omitempty"`
+	Schedulable  bool `yaml:"schedulable"`
+}
+
+// DAGTasks splits a vertex set into its DAG tasks, in the order of their first vertex
+func DAGTasks(vertices common.VertexSet) []DAGTask {
+	index := make(map[int]int, len(vertices))
+	for i, v := range vertices {
+		index[v.VertexID] = i
+	}
+
+	// the components of the precedence graph, with a union-find on the vertex indexes
+	parent := make([]int, len(vertices))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for i, v := range vertices {
+		for _, s := range v.Successors {
+			if j, ok := index[s]; ok {
+				parent[find(j)] = find(i)
+			}
+		}
+	}
+
+	var dags []DAGTask
+	var members [][]int
+	component := make(map[int]int)
+	for i := range vertices {
+		root := find(i)
+		c, ok := component[root]
+		if !ok {
+			c = len(dags)
+			component[root] = c
+			dags = append(dags, DAGTask{TaskID: vertices[i].TaskID, Period: vertices[i].Period,
+				Deadline: vertices[i].Deadline})
+			members = append(members, nil)
+		}
+		members[c] = append(members[c], i)
+	}
+	for c := range dags {
+		dag := &dags[c]
+		dag.Vertices = len(members[c])
+		for _, i := range members[c] {
+			v := vertices[i]
+			dag.TaskID = min(dag.TaskID, v.TaskID)
+			dag.Jitter = max(dag.Jitter, v.Jitter)
+			dag.Period = min(dag.Period, v.Period)
+			dag.Deadline = min(dag.Deadline, v.Deadline)
+			dag.Volume += v.WCET
+			if len(dag.taskIDs) == 0 || dag.taskIDs[len(dag.taskIDs)-1] != v.TaskID {
+				dag.taskIDs = append(dag.taskIDs, v.TaskID)
+			}
+		}
+		dag.CriticalPath = criticalPath(vertices, members[c], index)
+	}
+	return dags
+}
+
+// criticalPath returns the length of the longest path of a DAG, where a path is as long as the WCETs of its
+// vertices. A graph with a cycle has no longest path, so its whole volume is returned.
+func criticalPath(vertices common.VertexSet, members []int, index map[int]int) int {
+	inDegree := make(map[int]int, len(members))
+	for _, i := range members {
+		for _, s := range vertices[i].Successors {
+			if j, ok := index[s]; ok {
+				inDegree[j]++
+			}
+		}
+	}
+	// the longest path that ends at each vertex, in a topological order
+	finish := make(map[int]int, len(members))
+	var ready []int
+	for _, i := range members {
+		if inDegree[i] == 0 {
+			ready = append(ready, i)
+			finish[i] = vertices[i].WCET
+		}
+	}
+	longest, visited, volume := 0, 0, 0
+	for len(ready) > 0 {
+		i := ready[0]
+		ready = ready[1:]
+		visited++
+		longest = max(longest, finish[i])
+		for _, s := range vertices[i].Successors {
+			j, ok := index[s]
+			if !ok {
+				continue
+			}
+			finish[j] = max(finish[j], finish[i]+vertices[j].WCET)
+			inDegree[j]--
+			if inDegree[j] == 0 {
+				ready = append(ready, j)
+			}
+		}
+	}
+	if visited < len(members) {
+		for _, i := range members {
+			volume += vertices[i].WCET
+		}
+		return volume
+	}
+	return longest
+}
+
+// sporadic returns the deadline and the minimum inter-arrival time of the DAG in the model of the global tests
+func (dag DAGTask) sporadic() (d, t int) {
+	t = dag.Period - dag.Jitter
+	return min(dag.Deadline-dag.Jitter, t), t
+}
+
+// valid reports whether the DAG can meet its deadline at all
+func (dag DAGTask) valid() bool {
+	d, t := dag.sporadic()
+	return t > 0 && dag.CriticalPath <= d
+}
+
+// grahamBound returns the bound of R. L. Graham on the response time of the DAG alone on m cores
+func (dag DAGTask) grahamBound(m int) int {
+	return dag.CriticalPath + (dag.Volume-dag.CriticalPath)/m
+}
+
+// DAGPriorities returns the priority of each DAG under the policy, where a smaller value is a higher priority.
+// The explicit priority of a DAG is the highest priority of the tasks of its vertices.
+func DAGPriorities(dags []DAGTask, policy string, tasks common.TaskSet) ([]int, error) {
+	explicit := make(map[int]int, len(tasks))
+	for _, task := range tasks {
+		explicit[task.TaskID] = task.Priority
+	}
+	priorities := make([]int, len(dags))
+	for i, dag := range dags {
+		switch policy {
+		case RM:
+			priorities[i] = dag.Period
+		case DM:
+			priorities[i] = dag.Deadline
+		case DkC:
+			priorities[i] = dag.Deadline - dag.Volume
+		case Explicit:
+			for _, id := range dag.taskIDs {
+				priority := explicit[id]
+				if priority == 0 {
+					return nil, fmt.Errorf("task %d has no priority", id)
+				}
+				if priorities[i] == 0 || priority < priorities[i] {
+					priorities[i] = priority
+				}
+			}
+		case OPA:
+			return nil, fmt.Errorf("the %s priorities are only defined for the tasks of the response-time analysis",
+				policy)
+		default:
+			return nil, fmt.Errorf("unknown priority policy: %s", policy)
+		}
+	}
+	return priorities, nil
+}
+
+// dagResult returns the result of a DAG test with the characteristics of each DAG
+func dagResult(test string, dags []DAGTask, m int) Result {
+	result := Result{Test: test, Processors: m, Schedulable: true}
+	for _, dag := range dags {
+		result.Utilization += float64(dag.Volume) / float64(dag.Period)
+		result.DAGs = append(result.DAGs, DAGResult{
+			TaskID:       dag.TaskID,
+			Vertices:     dag.Vertices,
+			Period:       dag.Period,
+			Deadline:     dag.Deadline,
+			Volume:       dag.Volume,
+			CriticalPath: dag.CriticalPath,
+			GrahamBound:  dag.grahamBound(m) + dag.Jitter,
+		})
+	}
+	return result
+}
+
+// Graham checks each DAG alone on m cores with the bound of R. L. Graham, "Bounds on Multiprocessing Timing
+// Anomalies", (SIAM Journal on Applied Mathematics), 1969: a work-conserving schedule finishes the DAG within its
+// critical path plus the rest of its volume divided by m.
+func Graham(dags []DAGTask, m int) Result {
+	result := dagResult("graham", dags, m)
+	for i, dag := range dags {
+		d, _ := dag.sporadic()
+		schedulable := dag.valid() && dag.grahamBound(m) <= d
+		result.DAGs[i].Schedulable = schedulable
+		result.Schedulable = result.Schedulable && schedulable
+	}
+	return result
+}
+
+// MelaniResponseTimeAnalysis runs the G-FP response-time analysis of DAG tasks of A. Melani, M. Bertogna,
+// V. Bonifaci, A. Marchetti-Spaccamela, and G. Buttazzo, "Response-Time Analysis of Conditional DAG Tasks in
+// Multiprocessor Systems", (ECRTS 2015), 2015. The DAGs with the same priority are ordered by their index. The WCRT
+// of the DAGs after the first unschedulable DAG is -1.
+func MelaniResponseTimeAnalysis(dags []DAGTask, priorities []int, m int) Result {
+	order := make([]int, len(dags))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return priorities[order[a]] < priorities[order[b]]
+	})
+
+	result := dagResult("melani", dags, m)
+	responses := make([]int, len(dags))
+	for rank, k := range order {
+		wcrt := -1
+		if result.Schedulable && dags[k].valid() {
+			wcrt = melaniResponseTime(dags, order[:rank], responses, k, m)
+		}
+		responses[k] = wcrt
+		d, _ := dags[k].sporadic()
+		schedulable := wcrt != -1 && wcrt <= d
+		result.Schedulable = result.Schedulable && schedulable
+		if wcrt != -1 {
+			wcrt += dags[k].Jitter
+		}
+		result.DAGs[k].Priority = priorities[k]
+		result.DAGs[k].WCRT = wcrt
+		result.DAGs[k].Schedulable = schedulable
+	}
+	return result
+}
+
+// melaniResponseTime returns the response time of DAG k with the higher priority DAGs and their response times,
+// or the first response time found larger than its deadline
+func melaniResponseTime(dags []DAGTask, higher []int, responses []int, k, m int) int {
+	dagK := dags[k]
+	d, _ := dagK.sporadic()
+	r := dagK.grahamBound(m)
+	for r <= d {
+		interference := 0
+		for _, i := range higher {
+			_, t := dags[i].sporadic()
+			volume := dags[i].Volume
+			// the workload of DAG i in a window of length r with a carried-in job, scaled by m to stay in integers:
+			// the window starts when the carried-in job has volume / m of its work left
+			window := m*(r+responses[i]) - volume
+			if window <= 0 {
+				continue
+			}
+			jobs := window / (m * t)
+			interference += jobs*volume + min(volume, window-jobs*m*t)
+		}
+		next := dagK.CriticalPath + (dagK.Volume-dagK.CriticalPath+interference)/m
+		if next == r {
+			break
+		}
+		r = next
+	}
+	return r
+}
+
+// Federated allocates the DAGs to the cores with the federated scheduling of J. Li, J. J. Chen, K. Agrawal, C. Lu,
+// C. Gill, and A. Saifullah, "Analysis of Federated and Global Scheduling for Parallel Real-Time Tasks",
+// (ECRTS 2014), 2014, for constrained deadlines: each heavy DAG, whose volume is larger than its deadline, gets
+// its own ceil((volume - critical path) / (deadline - critical path)) cores, and the light DAGs run sequentially on
+// the remaining cores, first-fit by decreasing density with a total density of at most 1 per core (partitioned EDF).
+func Federated(dags []DAGTask, m int) Result {
+	result := dagResult("federated", dags, m)
+	result.Priority = "EDF"
+
+	used := 0
+	var light []int
+	for i, dag := range dags {
+		d, _ := dag.sporadic()
+		if dag.Volume <= d {
+			light = append(light, i)
+			continue
+		}
+		if !dag.valid() || dag.CriticalPath == d {
+			result.Schedulable = false
+			continue
+		}
+		cores := (dag.Volume - dag.CriticalPath + d - dag.CriticalPath - 1) / (d - dag.CriticalPath)
+		used += cores
+		result.DAGs[i].Cores = cores
+		result.DAGs[i].WCRT = dag.grahamBound(cores) + dag.Jitter
+		result.DAGs[i].Schedulable = used <= m
+		result.Schedulable = result.Schedulable && used <= m
+	}
+
+	density := func(i int) float64 {
+		d, _ := dags[i].sporadic()
+		return float64(dags[i].Volume) / float64(d)
+	}
+	sort.SliceStable(light, func(a, b int) bool {
+		return density(light[a]) > density(light[b])
+	})
+	shared := make([]float64, max(m-used, 0))
+	for _, i := range light {
+		placed := false
+		if dags[i].valid() {
+			for c := range shared {
+				if shared[c]+density(i) <= 1 {
+					shared[c] += density(i)
+					placed = true
+					break
+				}
+			}
+		}
+		result.DAGs[i].Schedulable = placed
+		result.Schedulable = result.Schedulable && placed
+	}
+	return result
+}
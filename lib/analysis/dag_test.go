@@ -0,0 +1,223 @@
+package analysis
+
+import (
+	"task-generator/lib/common"
+	"testing"
+)
+
+// dag creates the vertices of a DAG of a task with the given WCETs, numbered from first, and the successors of each
+// vertex by its index in the DAG
+func dag(taskID, first, period, deadline int, wcets []int, successors map[int][]int) common.VertexSet {
+	vertices := make(common.VertexSet, len(wcets))
+	for i, wcet := range wcets {
+		vertices[i] = &common.Vertex{TaskID: taskID, VertexID: first + i, WCET: wcet, Period: period,
+			Deadline: deadline}
+		for _, s := range successors[i] {
+			vertices[i].Successors = append(vertices[i].Successors, first+s)
+		}
+	}
+	return vertices
+}
+
+// forkJoin is a fork-join DAG of a source, three branches, and a sink: its critical path is 1 + 4 + 1 = 6 and its
+// volume 11
+func forkJoin(taskID, first, period, deadline int) common.VertexSet {
+	return dag(taskID, first, period, deadline, []int{1, 4, 2, 3, 1},
+		map[int][]int{0: {1, 2, 3}, 1: {4}, 2: {4}, 3: {4}})
+}
+
+// heavy is a fork-join DAG of a source, six branches of 4, and a sink, with a volume of 26 larger than its deadline
+// of 10 and a critical path of 6
+func heavy(taskID, first int) common.VertexSet {
+	return dag(taskID, first, 10, 10, []int{1, 4, 4, 4, 4, 4, 4, 1},
+		map[int][]int{0: {1, 2, 3, 4, 5, 6}, 1: {7}, 2: {7}, 3: {7}, 4: {7}, 5: {7}, 6: {7}})
+}
+
+// chain is a chain of two vertices with a volume and a critical path of 5
+func chain(taskID, first, period, deadline int) common.VertexSet {
+	return dag(taskID, first, period, deadline, []int{2, 3}, map[int][]int{0: {1}})
+}
+
+func TestDAGTasks(t *testing.T) {
+	tests := []struct {
+		name         string
+		vertices     common.VertexSet
+		volume       int
+		criticalPath int
+		vertexCount  int
+	}{
+		{"fork-join", forkJoin(0, 0, 20, 20), 11, 6, 5},
+		{"heavy", heavy(0, 0), 26, 6, 8},
+		{"chain", chain(0, 0, 20, 20), 5, 5, 2},
+		// the longest path goes through the vertex of 5 and not through the two vertices of 2
+		{"diamond with a shortcut", dag(0, 0, 20, 20, []int{1, 2, 2, 5, 1},
+			map[int][]int{0: {1, 3}, 1: {2}, 2: {4}, 3: {4}}), 11, 7, 5},
+		// a graph with a cycle has no longest path, so its whole volume is taken
+		{"cycle", dag(0, 0, 20, 20, []int{1, 2, 3}, map[int][]int{0: {1}, 1: {2}, 2: {0}}), 6, 6, 3},
+		{"single vertex", dag(0, 0, 20, 20, []int{4}, nil), 4, 4, 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dags := DAGTasks(test.vertices)
+			if len(dags) != 1 {
+				t.Fatalf("%d DAGs, want 1", len(dags))
+			}
+			if dags[0].Volume != test.volume {
+				t.Errorf("volume = %d, want %d", dags[0].Volume, test.volume)
+			}
+			if dags[0].CriticalPath != test.criticalPath {
+				t.Errorf("critical path = %d, want %d", dags[0].CriticalPath, test.criticalPath)
+			}
+			if dags[0].Vertices != test.vertexCount {
+				t.Errorf("%d vertices, want %d", dags[0].Vertices, test.vertexCount)
+			}
+		})
+	}
+}
+
+func TestDAGTasksComponents(t *testing.T) {
+	// the fork-join DAG of task 0 and a DAG of the vertices of tasks 3 and 5 with their own periods, as in the random
+	// DAGs, which is released with the shortest period and deadline and the largest jitter of its vertices
+	vertices := forkJoin(0, 0, 20, 20)
+	vertices = append(vertices,
+		&common.Vertex{TaskID: 5, VertexID: 5, WCET: 2, Period: 20, Deadline: 18, Jitter: 1, Successors: []int{6}},
+		&common.Vertex{TaskID: 3, VertexID: 6, WCET: 3, Period: 10, Deadline: 10, Jitter: 2})
+	dags := DAGTasks(vertices)
+	if len(dags) != 2 {
+		t.Fatalf("%d DAGs, want 2", len(dags))
+	}
+	want := DAGTask{TaskID: 3, Vertices: 2, Jitter: 2, Period: 10, Deadline: 10, Volume: 5, CriticalPath: 5}
+	got := dags[1]
+	if got.TaskID != want.TaskID || got.Vertices != want.Vertices || got.Jitter != want.Jitter ||
+		got.Period != want.Period || got.Deadline != want.Deadline || got.Volume != want.Volume ||
+		got.CriticalPath != want.CriticalPath {
+		t.Errorf("DAG = %+v, want %+v", got, want)
+	}
+
+	// the explicit priority of the DAG is the highest priority of its tasks
+	tasks := common.TaskSet{{TaskID: 0, Priority: 4}, {TaskID: 3, Priority: 7}, {TaskID: 5, Priority: 2}}
+	priorities, err := DAGPriorities(dags, Explicit, tasks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if priorities[0] != 4 || priorities[1] != 2 {
+		t.Errorf("priorities = %v, want [4 2]", priorities)
+	}
+	if _, err := DAGPriorities(dags, OPA, tasks); err == nil {
+		t.Error("OPA priorities of DAGs should fail")
+	}
+}
+
+func TestGraham(t *testing.T) {
+	tests := []struct {
+		name        string
+		deadline    int
+		m           int
+		bound       int
+		schedulable bool
+	}{
+		// the bound is 6 + floor((11 - 6) / m)
+		{"one core", 11, 1, 11, true},
+		{"two cores", 8, 2, 8, true},
+		{"two cores and a short deadline", 7, 2, 8, false},
+		{"four cores", 7, 4, 7, true},
+		{"deadline below the critical path", 5, 8, 6, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := Graham(DAGTasks(forkJoin(0, 0, 20, test.deadline)), test.m)
+			if result.Schedulable != test.schedulable {
+				t.Errorf("schedulable = %v, want %v", result.Schedulable, test.schedulable)
+			}
+			if bound := result.DAGs[0].GrahamBound; bound != test.bound {
+				t.Errorf("Graham bound = %d, want %d", bound, test.bound)
+			}
+		})
+	}
+}
+
+func TestMelaniResponseTimeAnalysis(t *testing.T) {
+	tests := []struct {
+		name        string
+		vertices    common.VertexSet
+		m           int
+		wcrt        []int
+		schedulable bool
+	}{
+		{
+			// the fork-join DAG has the higher priority and takes its Graham bound 6 + 5 / 2 = 8. The chain
+			// starts from its critical path 5 and the fork-join DAG interferes with its whole volume 11, as the
+			// window 2 * (5 + 8) - 11 = 15 is shorter than its period: R = 5 + 11 / 2 = 10
+			name:        "two DAGs",
+			vertices:    append(forkJoin(0, 0, 20, 20), chain(1, 5, 30, 30)...),
+			m:           2,
+			wcrt:        []int{8, 10},
+			schedulable: true,
+		},
+		{
+			// the chain takes 10 > 9 at the rate monotonic priority, so the DAG after it is not analyzed
+			name:        "unschedulable",
+			vertices:    append(append(forkJoin(0, 0, 20, 20), chain(1, 5, 30, 9)...), chain(2, 7, 40, 40)...),
+			m:           2,
+			wcrt:        []int{8, 10, -1},
+			schedulable: false,
+		},
+		{
+			// with four cores the fork-join DAG takes 6 + 5 / 4 = 7, and the chain 5 + 11 / 4 = 7
+			name:        "four cores",
+			vertices:    append(forkJoin(0, 0, 20, 20), chain(1, 5, 30, 30)...),
+			m:           4,
+			wcrt:        []int{7, 7},
+			schedulable: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dags := DAGTasks(test.vertices)
+			priorities, err := DAGPriorities(dags, RM, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result := MelaniResponseTimeAnalysis(dags, priorities, test.m)
+			if result.Schedulable != test.schedulable {
+				t.Errorf("schedulable = %v, want %v", result.Schedulable, test.schedulable)
+			}
+			for i, d := range result.DAGs {
+				if d.WCRT != test.wcrt[i] {
+					t.Errorf("WCRT of DAG %d = %d, want %d", i, d.WCRT, test.wcrt[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFederated(t *testing.T) {
+	tests := []struct {
+		name        string
+		vertices    common.VertexSet
+		m           int
+		cores       int
+		schedulable bool
+	}{
+		// the heavy DAG gets ceil((26 - 6) / (10 - 6)) = 5 cores, and finishes within 6 + 20 / 5 = 10
+		{"heavy DAG alone", heavy(0, 0), 5, 5, true},
+		{"too few cores for the heavy DAG", heavy(0, 0), 4, 5, false},
+		// the light chain runs sequentially on the sixth core
+		{"heavy and light DAGs", append(heavy(0, 0), chain(1, 8, 20, 20)...), 6, 5, true},
+		{"no core left for the light DAG", append(heavy(0, 0), chain(1, 8, 20, 20)...), 5, 5, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := Federated(DAGTasks(test.vertices), test.m)
+			if result.Schedulable != test.schedulable {
+				t.Errorf("schedulable = %v, want %v", result.Schedulable, test.schedulable)
+			}
+			if cores := result.DAGs[0].Cores; cores != test.cores {
+				t.Errorf("cores of the heavy DAG = %d, want %d", cores, test.cores)
+			}
+			if wcrt := result.DAGs[0].WCRT; wcrt != 10 {
+				t.Errorf("WCRT of the heavy DAG = %d, want 10", wcrt)
+			}
+		})
+	}
+}
@@ -0,0 +1,272 @@
+package analysis
+
+import (
+	"math"
+	"sort"
+	"task-generator/lib/common"
+)
+
+//	The global tests analyze sporadic tasks with constrained deadlines on m identical cores. A task with release jitter
+//	J is analyzed as a sporadic task with the minimum inter-arrival time T - J and the deadline D - J, and a deadline
+//	larger than the period is reduced to the period, which makes the tests only more pessimistic.
+
+// sporadicTask is a task in the model of the global tests
+type sporadicTask struct {
+	c, d, t int
+}
+
+// sporadicTasks converts the tasks to the model of the global tests
+func sporadicTasks(tasks common.TaskSet) []sporadicTask {
+	converted := make([]sporadicTask, len(tasks))
+	for i, task := range tasks {
+		t := task.Period - task.Jitter
+		converted[i] = sporadicTask{c: task.WCET, d: min(task.Deadline-task.Jitter, t), t: t}
+	}
+	return converted
+}
+
+// valid reports whether a task can meet its deadline at all
+func (task sporadicTask) valid() bool {
+	return task.t > 0 && task.c <= task.d
+}
+
+// utilization of the task
+func (task sporadicTask) utilization() float64 {
+	return float64(task.c) / float64(task.t)
+}
+
+// density of the task
+func (task sporadicTask) density() float64 {
+	return float64(task.c) / float64(task.d)
+}
+
+// globalResult returns the result of a global test with a verdict for each task
+func globalResult(test string, tasks common.TaskSet, m int, schedulable []bool) Result {
+	result := Result{Test: test, Utilization: tasks.Utilization(), Processors: m, Schedulable: true}
+	for i, task := range tasks {
+		result.Schedulable = result.Schedulable && schedulable[i]
+		result.Tasks = append(result.Tasks, TaskResult{
+			TaskID:      task.TaskID,
+			Deadline:    task.Deadline,
+			Schedulable: schedulable[i],
+		})
+	}
+	return result
+}
+
+// GFB runs the density test of G-EDF of J. Goossens, S. Funk, and S. Baruah, "Priority-Driven Scheduling of Periodic
+// Task Systems on Multiprocessors", (Real-Time Systems), 2003, extended to constrained deadlines with the densities:
+// the task set is schedulable on m cores if the total density is at most m - (m - 1) times the largest density.
+func GFB(tasks common.TaskSet, m int) Result {
+	result := Result{Test: "gfb", Priority: "EDF", Utilization: tasks.Utilization(), Processors: m}
+	total, largest := 0.0, 0.0
+	for _, task := range sporadicTasks(tasks) {
+		if !task.valid() {
+			return result
+		}
+		total += task.density()
+		largest = max(largest, task.density())
+	}
+	result.Schedulable = total <= float64(m)-float64(m-1)*largest
+	return result
+}
+
+// BCL runs the G-EDF test of M. Bertogna, M. Cirinei, and G. Lipari, "Improved Schedulability Analysis of EDF on
+// Multiprocessor Platforms", (ECRTS 2005), 2005, which bounds the interference on each task in the window of its
+// deadline.
+func BCL(tasks common.TaskSet, m int) Result {
+	sporadic := sporadicTasks(tasks)
+	schedulable := make([]bool, len(tasks))
+	for k, taskK := range sporadic {
+		if !taskK.valid() {
+			continue
+		}
+		lambda := taskK.density()
+		total := 0.0
+		strictlyLess := false
+		for i, taskI := range sporadic {
+			if i == k {
+				continue
+			}
+			// the jobs of task i in the window of task k, where the last one is partly in the window
+			jobs := 0
+			if taskK.d >= taskI.d {
+				jobs = (taskK.d-taskI.d)/taskI.t + 1
+			}
+			beta := float64(jobs*taskI.c+min(taskI.c, max(0, taskK.d-jobs*taskI.t))) / float64(taskK.d)
+			total += min(beta, 1-lambda)
+			if beta > 0 && beta <= 1-lambda {
+				strictlyLess = true
+			}
+		}
+		bound := float64(m) * (1 - lambda)
+		schedulable[k] = total < bound-1e-12 || (math.Abs(total-bound) <= 1e-12 && strictlyLess)
+	}
+	result := globalResult("bcl", tasks, m, schedulable)
+	result.Priority = "EDF"
+	return result
+}
+
+// GlobalResponseTimeAnalysis runs the G-FP response-time analysis of M. Bertogna and M. Cirinei, "Response-Time
+// Analysis for Globally Scheduled Symmetric Multiprocessor Platforms", (RTSS 2007), 2007. The tasks with the same
+// priority are ordered by their index. The WCRT of the tasks after the first unschedulable task is -1.
+func GlobalResponseTimeAnalysis(tasks common.TaskSet, priorities []int, m int) Result {
+	sporadic := sporadicTasks(tasks)
+	order := make([]int, len(tasks))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return priorities[order[a]] < priorities[order[b]]
+	})
+
+	result := Result{Test: "bc-rta", Utilization: tasks.Utilization(), Processors: m, Schedulable: true}
+	taskResults := make([]TaskResult, len(tasks))
+	responses := make([]int, len(tasks))
+	for rank, k := range order {
+		taskK := sporadic[k]
+		wcrt := -1
+		if result.Schedulable && taskK.valid() {
+			wcrt = globalResponseTime(sporadic, order[:rank], responses, k, m)
+		}
+		responses[k] = wcrt
+		schedulable := wcrt != -1 && wcrt <= taskK.d
+		result.Schedulable = result.Schedulable && schedulable
+		// the response time is measured from the nominal release, like in the uniprocessor analysis
+		if wcrt != -1 {
+			wcrt += tasks[k].Jitter
+		}
+		taskResults[k] = TaskResult{
+			TaskID:      tasks[k].TaskID,
+			PE:          tasks[k].PE,
+			Priority:    priorities[k],
+			Deadline:    tasks[k].Deadline,
+			WCRT:        wcrt,
+			Schedulable: schedulable,
+		}
+	}
+	result.Tasks = taskResults
+	return result
+}
+
+// globalResponseTime returns the response time of task k with the higher priority tasks and their response times,
+// or the first response time found larger than its deadline
+func globalResponseTime(tasks []sporadicTask, higher []int, responses []int, k, m int) int {
+	taskK := tasks[k]
+	r := taskK.c
+	for {
+		interference := 0
+		for _, i := range higher {
+			taskI := tasks[i]
+			// the workload of task i in a window of length r, with a carried-in job
+			jobs := (r + responses[i] - taskI.c) / taskI.t
+			workload := jobs*taskI.c + min(taskI.c, r+responses[i]-taskI.c-jobs*taskI.t)
+			interference += min(workload, r-taskK.c+1)
+		}
+		next := taskK.c + interference/m
+		if next == r || next > taskK.d {
+			return next
+		}
+		r = next
+	}
+}
+
+// maxBaruahPoints is the number of interval lengths of the Baruah test of a task after which the test gives up
+const maxBaruahPoints = 1000000
+
+// Baruah runs the G-EDF test of S. Baruah, "Techniques for Multiprocessor Global Schedulability Analysis",
+// (RTSS 2007), 2007, which checks the demand in the intervals before a deadline miss with at most m - 1 carried-in
+// jobs. A task is considered unschedulable if too many intervals have to be checked.
+func Baruah(tasks common.TaskSet, m int) Result {
+	sporadic := sporadicTasks(tasks)
+	schedulable := make([]bool, len(tasks))
+	totalUtilization := 0.0
+	for _, task := range sporadic {
+		totalUtilization += task.utilization()
+	}
+	valid := true
+	for _, task := range sporadic {
+		valid = valid && task.valid()
+	}
+	if valid && totalUtilization < float64(m) {
+		// the largest m - 1 execution times
+		executions := make([]int, len(sporadic))
+		slack := 0.0
+		for i, task := range sporadic {
+			executions[i] = task.c
+			slack += float64(task.t-task.d) * task.utilization()
+		}
+		sort.Sort(sort.Reverse(sort.IntSlice(executions)))
+		carried := 0
+		for i := 0; i < m-1 && i < len(executions); i++ {
+			carried += executions[i]
+		}
+
+		for k := range sporadic {
+			schedulable[k] = baruahTask(sporadic, k, m, carried, slack, totalUtilization)
+		}
+	}
+	result := globalResult("baruah", tasks, m, schedulable)
+	result.Priority = "EDF"
+	return result
+}
+
+// baruahTask checks the intervals of task k of the Baruah test
+func baruahTask(tasks []sporadicTask, k, m, carried int, slack, utilization float64) bool {
+	taskK := tasks[k]
+	// the intervals longer than the bound cannot have a deadline miss
+	bound := (float64(carried) - float64(taskK.d)*(float64(m)-utilization) + slack + float64(m*taskK.c)) /
+		(float64(m) - utilization)
+
+	// the demand only changes at the deadlines of the tasks, so only those interval lengths are checked
+	points := map[int]bool{0: true}
+	for _, task := range tasks {
+		for start := task.d - taskK.d; float64(start) < bound; start += task.t {
+			if start >= 0 {
+				points[start] = true
+			}
+			if len(points) > maxBaruahPoints {
+				return false
+			}
+		}
+	}
+
+	for a := range points {
+		if !baruahInterval(tasks, k, m, a) {
+			return false
+		}
+	}
+	return true
+}
+
+// baruahInterval checks the interval of length a + D_k before a deadline miss of task k
+func baruahInterval(tasks []sporadicTask, k, m, a int) bool {
+	taskK := tasks[k]
+	t := a + taskK.d
+	total := 0
+	var differences []int
+	for i, task := range tasks {
+		// the demand without a carried-in job, and with one
+		dbf := 0
+		if t >= task.d {
+			dbf = ((t-task.d)/task.t + 1) * task.c
+		}
+		carryIn := t/task.t*task.c + min(task.c, t%task.t)
+		var i1, i2 int
+		if i == k {
+			i1 = min(dbf-taskK.c, a)
+			i2 = min(carryIn-taskK.c, a)
+		} else {
+			i1 = min(dbf, t-taskK.c+1)
+			i2 = min(carryIn, t-taskK.c+1)
+		}
+		total += i1
+		differences = append(differences, max(i2-i1, 0))
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(differences)))
+	for i := 0; i < m-1 && i < len(differences); i++ {
+		total += differences[i]
+	}
+	// in discrete time, a deadline miss needs all the cores busy with the other jobs for a+D_k-C_k+1 time units
+	return total < m*(a+taskK.d-taskK.c+1)
+}
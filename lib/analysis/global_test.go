@@ -0,0 +1,217 @@
+package analysis
+
+import (
+	"math/rand"
+	"task-generator/lib/common"
+	"task-generator/lib/sim"
+	"testing"
+)
+
+// dhall is the example of the Dhall effect: two light tasks with the earlier deadlines take both cores at the same
+// time, and the heavy task finishes at 12 after its deadline 11, with a total utilization of 1.31 on 2 cores
+func dhall() common.TaskSet {
+	return taskSet([5]int{2, 10, 10, 0, 0}, [5]int{2, 10, 10, 0, 0}, [5]int{10, 11, 11, 0, 0})
+}
+
+func TestGlobalEDF(t *testing.T) {
+	tests := []struct {
+		name  string
+		tasks common.TaskSet
+		m     int
+		// the verdicts of GFB, BCL, and Baruah
+		gfb, bcl, baruah bool
+	}{
+		{
+			name:  "Dhall effect",
+			tasks: dhall(),
+			m:     2,
+			// the density bound is 2 - 0.91 = 1.09 < 1.31, and the interference of each light task on the heavy
+			// one is 3/11 > 1 - 10/11
+			gfb: false, bcl: false, baruah: false,
+		},
+		{
+			// the total density 1.5 is the bound 2 - 0.5, and the interference of each task on the others is
+			// 1/2 = 1 - 1/2, which BCL accepts as it is not larger
+			name:  "density at the GFB bound",
+			tasks: taskSet([5]int{1, 2, 2, 0, 0}, [5]int{1, 2, 2, 0, 0}, [5]int{1, 2, 2, 0, 0}),
+			m:     2,
+			gfb:   true, bcl: true, baruah: true,
+		},
+		{
+			// the heavy task has a density of 0.8, so the density bound is 4 - 3 * 0.8 = 1.6 < 1.7, while the
+			// interference of each light task on the heavy one is capped at 1 - 0.8, which leaves it enough room
+			name: "heavy task on 4 cores",
+			tasks: taskSet([5]int{8, 10, 10, 0, 0}, [5]int{3, 10, 10, 0, 0}, [5]int{3, 10, 10, 0, 0},
+				[5]int{3, 10, 10, 0, 0}),
+			m:   4,
+			gfb: false, bcl: true, baruah: true,
+		},
+		{
+			name:  "utilization above the cores",
+			tasks: taskSet([5]int{3, 4, 4, 0, 0}, [5]int{3, 4, 4, 0, 0}, [5]int{3, 4, 4, 0, 0}),
+			m:     2,
+			gfb:   false, bcl: false, baruah: false,
+		},
+		{
+			// the jitter leaves less time than the WCET
+			name:  "jitter larger than the slack",
+			tasks: taskSet([5]int{1, 10, 10, 0, 0}, [5]int{5, 10, 10, 6, 0}),
+			m:     2,
+			gfb:   false, bcl: false, baruah: false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, check := range []struct {
+				result Result
+				want   bool
+			}{
+				{GFB(test.tasks, test.m), test.gfb},
+				{BCL(test.tasks, test.m), test.bcl},
+				{Baruah(test.tasks, test.m), test.baruah},
+			} {
+				if check.result.Schedulable != check.want {
+					t.Errorf("%s: schedulable = %v, want %v", check.result.Test, check.result.Schedulable,
+						check.want)
+				}
+				if check.result.Processors != test.m || check.result.Priority != "EDF" {
+					t.Errorf("%s: %d processors with %s", check.result.Test, check.result.Processors,
+						check.result.Priority)
+				}
+			}
+		})
+	}
+}
+
+func TestGlobalResponseTimeAnalysis(t *testing.T) {
+	tests := []struct {
+		name        string
+		tasks       common.TaskSet
+		m           int
+		wcrt        []int
+		schedulable bool
+	}{
+		{
+			// the two tasks of the highest priorities run in parallel, and delay the third task by one time unit
+			// in its window of 4: R3 = 3 + floor((1 + 1) / 2) = 4
+			name:        "three tasks",
+			tasks:       taskSet([5]int{1, 4, 4, 0, 0}, [5]int{1, 4, 4, 0, 0}, [5]int{3, 6, 6, 0, 0}),
+			m:           2,
+			wcrt:        []int{1, 1, 4},
+			schedulable: true,
+		},
+		{
+			// the interference grows with the window: R3 = 5 + 1 = 6, then 5 + floor((2 + 2) / 2) = 7 > 6, and the
+			// task after it is not analyzed
+			name: "unschedulable",
+			tasks: taskSet([5]int{1, 4, 4, 0, 0}, [5]int{1, 4, 4, 0, 0}, [5]int{5, 6, 6, 0, 0},
+				[5]int{1, 20, 20, 0, 0}),
+			m:           2,
+			wcrt:        []int{1, 1, 7, -1},
+			schedulable: false,
+		},
+		{
+			// the Dhall effect with the rate monotonic priorities: R3 = 10 + 1 = 11, then 10 + floor((2 + 2) / 2)
+			name:        "Dhall effect",
+			tasks:       dhall(),
+			m:           2,
+			wcrt:        []int{2, 2, 12},
+			schedulable: false,
+		},
+		{
+			// the response time is measured from the nominal release, so it includes the jitter
+			name:        "release jitter",
+			tasks:       taskSet([5]int{1, 4, 4, 0, 0}, [5]int{2, 8, 8, 2, 0}),
+			m:           2,
+			wcrt:        []int{1, 4},
+			schedulable: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			priorities, err := Priorities(test.tasks, RM)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result := GlobalResponseTimeAnalysis(test.tasks, priorities, test.m)
+			if result.Schedulable != test.schedulable {
+				t.Errorf("schedulable = %v, want %v", result.Schedulable, test.schedulable)
+			}
+			for i, task := range result.Tasks {
+				if task.WCRT != test.wcrt[i] {
+					t.Errorf("WCRT of task %d = %d, want %d", i, task.WCRT, test.wcrt[i])
+				}
+			}
+		})
+	}
+}
+
+// simulateGlobal simulates the synchronous periodic jobs of the tasks in a hyperperiod with G-EDF or G-FP, and
+// reports whether all the deadlines are met
+func simulateGlobal(t *testing.T, tasks common.TaskSet, priorities []int, m int) bool {
+	var jobs common.JobSet
+	hyperperiod := tasks.HyperPeriod()
+	for i, task := range tasks {
+		for j, release := 0, 0; release < hyperperiod; j, release = j+1, release+task.Period {
+			priority := release + task.Deadline
+			if priorities != nil {
+				priority = priorities[i]
+			}
+			jobs = append(jobs, &common.Job{Task: task, TaskID: i, JobID: j, EarliestArrivalTime: release,
+				LatestArrivalTime: release, AbsoluteDeadline: release + task.Deadline, Priority: priority})
+		}
+	}
+	s, err := sim.New(sim.WithCores(m), sim.WithSeed(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := s.Run(jobs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result.Schedulable
+}
+
+func TestGlobalTestsAreSufficient(t *testing.T) {
+	// the tests are only sufficient, so a task set that they accept meets all its deadlines in the synchronous
+	// periodic schedule
+	rng := rand.New(rand.NewSource(1))
+	accepted := make(map[string]int)
+	for set := 0; set < 500; set++ {
+		m := 2 + rng.Intn(3)
+		var tasks common.TaskSet
+		for i := 0; i < m+1+rng.Intn(2*m); i++ {
+			period := []int{4, 5, 8, 10, 20}[rng.Intn(5)]
+			wcet := 1 + rng.Intn(period/2)
+			deadline := wcet + rng.Intn(period-wcet+1)
+			tasks = append(tasks, &common.Task{TaskID: i, WCET: wcet, Period: period, Deadline: deadline})
+		}
+		priorities, err := Priorities(tasks, DM)
+		if err != nil {
+			t.Fatal(err)
+		}
+		edf := simulateGlobal(t, tasks, nil, m)
+		fp := simulateGlobal(t, tasks, priorities, m)
+		for _, check := range []struct {
+			result      Result
+			schedulable bool
+		}{
+			{GFB(tasks, m), edf},
+			{BCL(tasks, m), edf},
+			{Baruah(tasks, m), edf},
+			{GlobalResponseTimeAnalysis(tasks, priorities, m), fp},
+		} {
+			if check.result.Schedulable {
+				accepted[check.result.Test]++
+				if !check.schedulable {
+					t.Fatalf("%s accepts %v on %d cores, which misses a deadline", check.result.Test, tasks, m)
+				}
+			}
+		}
+	}
+	for _, test := range []string{"gfb", "bcl", "baruah", "bc-rta"} {
+		if accepted[test] == 0 {
+			t.Errorf("%s accepts no task set", test)
+		}
+	}
+}
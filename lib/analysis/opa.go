@@ -0,0 +1,52 @@
+package analysis
+
+import (
+	"sort"
+	"task-generator/lib/common"
+)
+
+// opaPriorities returns the priorities of Audsley's Optimal Priority Assignment, N. C. Audsley, "On Priority
+// Assignment in Fixed Priority Scheduling", (Information Processing Letters), 2001, with the response-time analysis
+// of each core as the schedulability test: from the lowest priority up, a level goes to a task that is schedulable
+// with all the unassigned tasks of its core at higher priorities. The candidates of a level are tried in the reverse
+// deadline monotonic order. If no task fits a level, the unassigned tasks of the core get the higher levels in the
+// deadline monotonic order, and the task set is not schedulable with fixed priorities on this mapping.
+// The priorities are the levels 1, 2, ... on each core, where 1 is the highest.
+func opaPriorities(tasks common.TaskSet) []int {
+	priorities := make([]int, len(tasks))
+	cores := make(map[int][]int)
+	for i, task := range tasks {
+		cores[task.PE] = append(cores[task.PE], i)
+	}
+	for _, unassigned := range cores {
+		// deadline monotonic order, so the candidates are tried from the longest deadline
+		sort.SliceStable(unassigned, func(a, b int) bool {
+			return tasks[unassigned[a]].Deadline < tasks[unassigned[b]].Deadline
+		})
+		for level := len(unassigned); level > 0; level-- {
+			found := -1
+			for k := len(unassigned) - 1; k >= 0; k-- {
+				var higher common.TaskSet
+				for _, j := range unassigned {
+					if j != unassigned[k] {
+						higher = append(higher, tasks[j])
+					}
+				}
+				candidate := tasks[unassigned[k]]
+				if wcrt := responseTime(candidate, higher); wcrt != -1 && wcrt <= candidate.Deadline {
+					found = k
+					break
+				}
+			}
+			if found == -1 {
+				for k, j := range unassigned {
+					priorities[j] = k + 1
+				}
+				break
+			}
+			priorities[unassigned[found]] = level
+			unassigned = append(unassigned[:found], unassigned[found+1:]...)
+		}
+	}
+	return priorities
+}
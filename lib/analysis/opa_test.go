@@ -0,0 +1,79 @@
+package analysis
+
+import (
+	"task-generator/lib/common"
+	"testing"
+)
+
+func TestOPA(t *testing.T) {
+	tests := []struct {
+		name        string
+		tasks       common.TaskSet
+		priorities  []int
+		schedulable bool
+	}{
+		{
+			// deadline monotonic is not optimal with deadlines larger than the periods: with the first task at the
+			// higher priority, the second one takes 156 > 154, while with the second task at the higher priority,
+			// the first one takes at most 108 <= 110
+			name:        "arbitrary deadlines",
+			tasks:       taskSet([5]int{52, 100, 110, 0, 0}, [5]int{52, 140, 154, 0, 0}),
+			priorities:  []int{2, 1},
+			schedulable: true,
+		},
+		{
+			// with constrained deadlines and no jitter, OPA finds the deadline monotonic order
+			name:        "Buttazzo",
+			tasks:       taskSet([5]int{3, 10, 10, 0, 0}, [5]int{1, 4, 4, 0, 0}, [5]int{2, 6, 6, 0, 0}),
+			priorities:  []int{3, 1, 2},
+			schedulable: true,
+		},
+		{
+			// the levels are assigned on each core
+			name: "partitioned",
+			tasks: taskSet([5]int{52, 100, 110, 0, 0}, [5]int{1, 4, 4, 0, 1}, [5]int{52, 140, 154, 0, 0},
+				[5]int{2, 6, 6, 0, 1}),
+			priorities:  []int{2, 1, 1, 2},
+			schedulable: true,
+		},
+		{
+			// no task fits the lowest level, so the tasks keep the deadline monotonic order
+			name:        "unschedulable",
+			tasks:       taskSet([5]int{4, 10, 10, 0, 0}, [5]int{2, 4, 4, 0, 0}, [5]int{2, 6, 5, 0, 0}),
+			priorities:  []int{3, 1, 2},
+			schedulable: false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			priorities, err := Priorities(test.tasks, OPA)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, priority := range priorities {
+				if priority != test.priorities[i] {
+					t.Errorf("priority of task %d = %d, want %d", i, priority, test.priorities[i])
+				}
+			}
+			if result := ResponseTimeAnalysis(test.tasks, priorities); result.Schedulable != test.schedulable {
+				t.Errorf("schedulable = %v, want %v", result.Schedulable, test.schedulable)
+			}
+		})
+	}
+}
+
+func TestOPADominatesDM(t *testing.T) {
+	// the deadline monotonic order misses the deadline of the arbitrary deadline example that OPA schedules
+	tasks := taskSet([5]int{52, 100, 110, 0, 0}, [5]int{52, 140, 154, 0, 0})
+	priorities, err := Priorities(tasks, DM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := ResponseTimeAnalysis(tasks, priorities)
+	if result.Schedulable {
+		t.Error("the deadline monotonic order should miss a deadline")
+	}
+	if wcrt := result.Tasks[1].WCRT; wcrt != 156 {
+		t.Errorf("WCRT of task 1 = %d, want 156", wcrt)
+	}
+}
@@ -0,0 +1,157 @@
+package analysis
+
+import (
+	"sort"
+	"task-generator/lib/common"
+)
+
+// CoreResult is the result of the analysis of the tasks of a core
+type CoreResult struct {
+	PE          int     `yaml:"pe"`
+	Utilization float64 `yaml:"utilization"`
+	// Interval is the length of the interval in which the demand is checked
+	Interval    int  `yaml:"interval"`
+	Schedulable bool `yaml:"schedulable"`
+	// Overload is the first time found where the demand exceeds the time, or 0 if there is none
+	Overload int `yaml:"overload,omitempty"`
+}
+
+// QPA runs the EDF processor demand analysis of the tasks of each core with the Quick Processor-demand Analysis of
+// F. Zhang and A. Burns, "Schedulability Analysis for Real-Time Systems with EDF Scheduling", (IEEE Transactions on
+// Computers), 2009. The release jitter of a task shortens its deadline, as the demand bound function of a task is
+// max(0, floor((t + J - D) / T) + 1) * C.
+func QPA(tasks common.TaskSet) Result {
+	result := Result{Test: "qpa", Priority: "EDF", Utilization: tasks.Utilization(), Schedulable: true}
+	for _, pe := range cores(tasks) {
+		var coreTasks common.TaskSet
+		for _, task := range tasks {
+			if task.PE == pe {
+				coreTasks = append(coreTasks, task)
+			}
+		}
+		core := qpaCore(coreTasks)
+		core.PE = pe
+		result.Schedulable = result.Schedulable && core.Schedulable
+		result.Cores = append(result.Cores, core)
+	}
+	return result
+}
+
+// cores returns the cores the tasks are mapped to, in increasing order
+func cores(tasks common.TaskSet) []int {
+	seen := make(map[int]bool)
+	var pes []int
+	for _, task := range tasks {
+		if !seen[task.PE] {
+			seen[task.PE] = true
+			pes = append(pes, task.PE)
+		}
+	}
+	sort.Ints(pes)
+	return pes
+}
+
+// demand returns the processor demand of the tasks in an interval of length t
+func demand(tasks common.TaskSet, t int) int {
+	total := 0
+	for _, task := range tasks {
+		if d := task.Deadline - task.Jitter; t >= d {
+			total += ((t-d)/task.Period + 1) * task.WCET
+		}
+	}
+	return total
+}
+
+// lastDeadline returns the largest absolute deadline of the tasks smaller than t, or -1 if there is none
+func lastDeadline(tasks common.TaskSet, t int) int {
+	last := -1
+	for _, task := range tasks {
+		if d := task.Deadline - task.Jitter; t > d {
+			last = max(last, d+(t-1-d)/task.Period*task.Period)
+		}
+	}
+	return last
+}
+
+// qpaCore runs the processor demand analysis of the tasks of a core
+func qpaCore(tasks common.TaskSet) CoreResult {
+	core := CoreResult{Utilization: tasks.Utilization()}
+	if core.Utilization > 1 {
+		return core
+	}
+	minDeadline := -1
+	for _, task := range tasks {
+		d := task.Deadline - task.Jitter
+		// the jitter leaves no time for the execution
+		if d < task.WCET {
+			core.Overload = max(d, 0)
+			return core
+		}
+		if minDeadline == -1 || d < minDeadline {
+			minDeadline = d
+		}
+	}
+
+	core.Interval = demandInterval(tasks, core.Utilization)
+	if core.Interval == -1 {
+		return core
+	}
+	t := lastDeadline(tasks, core.Interval)
+	h := demand(tasks, t)
+	// without a deadline in the interval, the demand cannot exceed the time
+	for t != -1 && h <= t && h > minDeadline {
+		if h < t {
+			t = h
+		} else {
+			t = lastDeadline(tasks, t)
+		}
+		h = demand(tasks, t)
+	}
+	if t != -1 && h > t {
+		core.Overload = t
+		return core
+	}
+	core.Schedulable = true
+	return core
+}
+
+// demandInterval returns the length of the interval in which the demand has to be checked, i.e., the smaller of the
+// bound of the utilization and the synchronous busy period. With a utilization of 1, the busy period may not end, but
+// the demand repeats itself after the largest deadline every hyperperiod. It returns -1 if the hyperperiod is too large.
+func demandInterval(tasks common.TaskSet, utilization float64) int {
+	largest := 0
+	for _, task := range tasks {
+		largest = max(largest, task.Deadline-task.Jitter)
+	}
+	// a utilization that is 1 up to rounding errors
+	if utilization >= 1-1e-9 {
+		hyperperiod := tasks.HyperPeriod()
+		if hyperperiod == -1 {
+			return -1
+		}
+		return hyperperiod + largest + 1
+	}
+
+	bound := 0.0
+	for _, task := range tasks {
+		bound += float64(task.Period-task.Deadline+task.Jitter) * float64(task.WCET) / float64(task.Period)
+	}
+	interval := int(max(float64(largest), bound/(1-utilization))) + 1
+
+	// the synchronous busy period, where the jobs are released as early as the jitter allows
+	busy := 0
+	for _, task := range tasks {
+		busy += task.WCET
+	}
+	for busy < interval {
+		next := 0
+		for _, task := range tasks {
+			next += ceilDiv(busy+task.Jitter, task.Period) * task.WCET
+		}
+		if next == busy {
+			return busy
+		}
+		busy = next
+	}
+	return interval
+}
@@ -0,0 +1,114 @@
+package analysis
+
+import (
+	"math/rand"
+	"task-generator/lib/common"
+	"testing"
+)
+
+// bruteForceEDF checks the demand bound function of the tasks of one core at every time up to the hyperperiod plus
+// the largest deadline, after which the demand repeats itself
+func bruteForceEDF(tasks common.TaskSet) bool {
+	if tasks.Utilization() > 1 {
+		return false
+	}
+	largest := 0
+	for _, task := range tasks {
+		largest = max(largest, task.Deadline-task.Jitter)
+	}
+	for t := 0; t <= tasks.HyperPeriod()+largest; t++ {
+		dbf := 0
+		for _, task := range tasks {
+			if window := t + task.Jitter - task.Deadline; window >= 0 {
+				dbf += (window/task.Period + 1) * task.WCET
+			}
+		}
+		if dbf > t {
+			return false
+		}
+	}
+	return true
+}
+
+func TestQPA(t *testing.T) {
+	tests := []struct {
+		name        string
+		tasks       common.TaskSet
+		schedulable bool
+		overload    int
+	}{
+		{
+			name:        "implicit deadlines at full utilization",
+			tasks:       taskSet([5]int{1, 4, 4, 0, 0}, [5]int{2, 6, 6, 0, 0}, [5]int{5, 12, 12, 0, 0}),
+			schedulable: true,
+		},
+		{
+			// the demand at 3 is 1 + 2 + 1 = 4
+			name:        "constrained deadlines",
+			tasks:       taskSet([5]int{1, 4, 3, 0, 0}, [5]int{2, 6, 3, 0, 0}, [5]int{1, 12, 3, 0, 0}),
+			schedulable: false,
+			overload:    3,
+		},
+		{
+			// the jitter shortens the deadline of the first task to 2, so the demand at 3 is 2 + 2
+			name:        "release jitter",
+			tasks:       taskSet([5]int{2, 8, 4, 2, 0}, [5]int{2, 8, 3, 0, 0}),
+			schedulable: false,
+			overload:    3,
+		},
+		{
+			name:        "jitter without time to execute",
+			tasks:       taskSet([5]int{3, 10, 5, 3, 0}),
+			schedulable: false,
+			overload:    2,
+		},
+		{
+			name:        "utilization above 1",
+			tasks:       taskSet([5]int{3, 5, 5, 0, 0}, [5]int{3, 5, 5, 0, 0}),
+			schedulable: false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := QPA(test.tasks)
+			if result.Schedulable != test.schedulable {
+				t.Errorf("schedulable = %v, want %v", result.Schedulable, test.schedulable)
+			}
+			if overload := result.Cores[0].Overload; overload != test.overload {
+				t.Errorf("overload = %d, want %d", overload, test.overload)
+			}
+		})
+	}
+}
+
+func TestQPAAgainstDemandBound(t *testing.T) {
+	// random task sets of one core with small periods, so the demand bound function can be checked at every time
+	rng := rand.New(rand.NewSource(1))
+	verdicts := make(map[bool]int)
+	for set := 0; set < 2000; set++ {
+		var tasks common.TaskSet
+		for i := 0; i < 2+rng.Intn(4); i++ {
+			period := 2 + rng.Intn(19)
+			wcet := 1 + rng.Intn(max(period/3, 1))
+			jitter := rng.Intn(3)
+			deadline := wcet + rng.Intn(period+4-wcet)
+			tasks = append(tasks, &common.Task{TaskID: i, WCET: wcet, Period: period, Deadline: deadline,
+				Jitter: jitter})
+		}
+		want := bruteForceEDF(tasks)
+		verdicts[want]++
+		result := QPA(tasks)
+		if result.Schedulable != want {
+			t.Fatalf("QPA of %v = %v, the demand bound function gives %v", tasks, result.Schedulable, want)
+		}
+		// the overload is a time where the demand exceeds the time
+		if overload := result.Cores[0].Overload; overload > 0 && demand(tasks, overload) <= overload {
+			t.Fatalf("QPA of %v reports the overload %d with the demand %d", tasks, overload,
+				demand(tasks, overload))
+		}
+	}
+	// both verdicts have to be covered
+	if verdicts[true] < 100 || verdicts[false] < 100 {
+		t.Errorf("%d schedulable and %d unschedulable task sets", verdicts[true], verdicts[false])
+	}
+}
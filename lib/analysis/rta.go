@@ -0,0 +1,162 @@
+package analysis
+
+import (
+	"fmt"
+	"task-generator/lib/common"
+)
+
+// the fixed-priority policies of the analysis
+const (
+	// RM is Rate Monotonic, the shorter the period the higher the priority
+	RM = "RM"
+	// DM is Deadline Monotonic, the shorter the deadline the higher the priority
+	DM = "DM"
+	// DkC is D - C Monotonic, the shorter the deadline minus the WCET the higher the priority
+	DkC = "DkC"
+	// OPA is Audsley's Optimal Priority Assignment with the response-time analysis
+	OPA = "OPA"
+	// Explicit takes the priorities of the task set
+	Explicit = "explicit"
+)
+
+// PriorityPolicies are the fixed-priority policies of the analysis
+var PriorityPolicies = []string{RM, DM, DkC, OPA, Explicit}
+
+// maxBusyJobs is the number of jobs of a task in a busy period after which the response time is considered unbounded
+const maxBusyJobs = 1000000
+
+// TaskResult is the result of the analysis of a task
+type TaskResult struct {
+	TaskID      int  `yaml:"task_id"`
+	PE          int  `yaml:"pe"`
+	Priority    int  `yaml:"priority"`
+	Deadline    int  `yaml:"deadline"`
+	WCRT        int  `yaml:"wcrt"`
+	Schedulable bool `yaml:"schedulable"`
+}
+
+// Result is the result of the analysis of a task set, with the results of its tasks or of its cores
+type Result struct {
+	Test        string       `yaml:"test"`
+	Priority    string       `yaml:"priority"`
+	Utilization float64      `yaml:"utilization"`
+	Processors  int          `yaml:"processors,omitempty"`
+	Schedulable bool         `yaml:"schedulable"`
+	Tasks       []TaskResult `yaml:"tasks,omitempty"`
+	Cores       []CoreResult `yaml:"cores,omitempty"`
+	DAGs        []DAGResult  `yaml:"dags,omitempty"`
+}
+
+// Priorities returns the priority of each task under the policy, where a smaller value is a higher priority.
+// The OPA priorities are assigned on each core, for the response-time analysis of the partitioned tasks.
+func Priorities(tasks common.TaskSet, policy string) ([]int, error) {
+	if policy == OPA {
+		return opaPriorities(tasks), nil
+	}
+	priorities := make([]int, len(tasks))
+	for i, task := range tasks {
+		switch policy {
+		case RM:
+			priorities[i] = task.Period
+		case DM:
+			priorities[i] = task.Deadline
+		case DkC:
+			priorities[i] = task.Deadline - task.WCET
+		case Explicit:
+			if task.Priority == 0 {
+				return nil, fmt.Errorf("task %d has no priority", task.TaskID)
+			}
+			priorities[i] = task.Priority
+		default:
+			return nil, fmt.Errorf("unknown priority policy: %s", policy)
+		}
+	}
+	return priorities, nil
+}
+
+// ResponseTimeAnalysis runs the uniprocessor response-time analysis with release jitter of the tasks of each core,
+// i.e., the tasks are partitioned by their PE. The tasks with the same priority interfere with each other.
+// The deadlines can be larger than the periods, in which case all the jobs of the level-i busy period are analyzed.
+// The WCRT of an unschedulable task is the first response time found larger than its deadline, or -1 if it is
+// unbounded.
+func ResponseTimeAnalysis(tasks common.TaskSet, priorities []int) Result {
+	result := Result{Test: "rta", Utilization: tasks.Utilization(), Schedulable: true}
+	for i, task := range tasks {
+		// the tasks on the same core with the same or a higher priority
+		var higher common.TaskSet
+		for j, other := range tasks {
+			if j != i && other.PE == task.PE && priorities[j] <= priorities[i] {
+				higher = append(higher, other)
+			}
+		}
+		wcrt := responseTime(task, higher)
+		schedulable := wcrt != -1 && wcrt <= task.Deadline
+		result.Schedulable = result.Schedulable && schedulable
+		result.Tasks = append(result.Tasks, TaskResult{
+			TaskID:      task.TaskID,
+			PE:          task.PE,
+			Priority:    priorities[i],
+			Deadline:    task.Deadline,
+			WCRT:        wcrt,
+			Schedulable: schedulable,
+		})
+	}
+	return result
+}
+
+// utilization returns the utilization of a task and the tasks that interfere with it
+func utilization(task *common.Task, higher common.TaskSet) float64 {
+	return float64(task.WCET)/float64(task.Period) + higher.Utilization()
+}
+
+// responseTime returns the WCRT of a task with the interfering tasks, as in K. Tindell, A. Burns, and A. Wellings,
+// "An Extendible Approach for Analyzing Fixed Priority Hard Real-Time Tasks", (Real-Time Systems), 1994.
+// It stops as soon as a response time is larger than the deadline.
+func responseTime(task *common.Task, higher common.TaskSet) int {
+	if utilization(task, higher) > 1 {
+		return -1
+	}
+	// interference is the execution of the interfering jobs released in a window of length w
+	interference := func(w int) int {
+		total := 0
+		for _, other := range higher {
+			total += ceilDiv(w+other.Jitter, other.Period) * other.WCET
+		}
+		return total
+	}
+
+	wcrt := 0
+	w := 0
+	for q := 0; q < maxBusyJobs; q++ {
+		// the completion of the (q+1)-th job of the busy period
+		w = max(w, (q+1)*task.WCET)
+		for {
+			next := (q+1)*task.WCET + interference(w)
+			if next == w {
+				break
+			}
+			w = next
+			if w-q*task.Period+task.Jitter > task.Deadline {
+				break
+			}
+		}
+		response := w - q*task.Period + task.Jitter
+		wcrt = max(wcrt, response)
+		if response > task.Deadline {
+			return wcrt
+		}
+		// the busy period ends before the release of the next job
+		if w+task.Jitter <= (q+1)*task.Period {
+			return wcrt
+		}
+	}
+	return -1
+}
+
+// ceilDiv returns the ceiling of a / b for positive b
+func ceilDiv(a, b int) int {
+	if a <= 0 {
+		return 0
+	}
+	return (a + b - 1) / b
+}
@@ -0,0 +1,140 @@
+package analysis
+
+import (
+	"task-generator/lib/common"
+	"testing"
+)
+
+// taskSet creates a task set of the tasks given as {C, T, D, J, PE}, numbered in their order
+func taskSet(params ...[5]int) common.TaskSet {
+	tasks := make(common.TaskSet, len(params))
+	for i, p := range params {
+		tasks[i] = &common.Task{TaskID: i, WCET: p[0], Period: p[1], Deadline: p[2], Jitter: p[3], PE: p[4]}
+	}
+	return tasks
+}
+
+func TestResponseTimeAnalysis(t *testing.T) {
+	tests := []struct {
+		name        string
+		tasks       common.TaskSet
+		priorities  []int
+		wcrt        []int
+		schedulable bool
+	}{
+		{
+			// the response-time analysis example of G. C. Buttazzo, "Hard Real-Time Computing Systems", chapter 4:
+			// R3 = 3 + ceil(10 / 4) * 1 + ceil(10 / 6) * 2 = 10
+			name:        "Buttazzo",
+			tasks:       taskSet([5]int{1, 4, 4, 0, 0}, [5]int{2, 6, 6, 0, 0}, [5]int{3, 10, 10, 0, 0}),
+			priorities:  []int{4, 6, 10},
+			wcrt:        []int{1, 3, 10},
+			schedulable: true,
+		},
+		{
+			// the WCET of the last task is one more than in the Buttazzo example: w = 4 + 3 * 1 + 2 * 2 = 11
+			name:        "Buttazzo overloaded",
+			tasks:       taskSet([5]int{1, 4, 4, 0, 0}, [5]int{2, 6, 6, 0, 0}, [5]int{4, 10, 10, 0, 0}),
+			priorities:  []int{4, 6, 10},
+			wcrt:        []int{1, 3, 11},
+			schedulable: false,
+		},
+		{
+			// J. P. Lehoczky, "Fixed Priority Scheduling of Periodic Task Sets with Arbitrary Deadlines", (RTSS
+			// 1990): the first job of the second task takes 114, while the fifth job of its busy period takes 118
+			name:        "Lehoczky arbitrary deadlines",
+			tasks:       taskSet([5]int{26, 70, 70, 0, 0}, [5]int{62, 100, 120, 0, 0}),
+			priorities:  []int{1, 2},
+			wcrt:        []int{26, 118},
+			schedulable: true,
+		},
+		{
+			// with D = T, the analysis stops at the first job of the second task
+			name:        "Lehoczky implicit deadlines",
+			tasks:       taskSet([5]int{26, 70, 70, 0, 0}, [5]int{62, 100, 100, 0, 0}),
+			priorities:  []int{1, 2},
+			wcrt:        []int{26, 114},
+			schedulable: false,
+		},
+		{
+			// the jitter of the first task lets two of its jobs hit the second one, and the jitter of the second
+			// task adds to its response time: w = 2 + ceil((w + 3) / 4) * 1 = 4, and R = 4 + 1
+			name:        "release jitter",
+			tasks:       taskSet([5]int{1, 4, 4, 3, 0}, [5]int{2, 6, 6, 1, 0}),
+			priorities:  []int{1, 2},
+			wcrt:        []int{4, 5},
+			schedulable: true,
+		},
+		{
+			// the tasks with the same priority interfere with each other
+			name:        "same priority",
+			tasks:       taskSet([5]int{2, 10, 10, 0, 0}, [5]int{3, 10, 10, 0, 0}),
+			priorities:  []int{1, 1},
+			wcrt:        []int{5, 5},
+			schedulable: true,
+		},
+		{
+			// the tasks of the other cores do not interfere
+			name:        "partitioned",
+			tasks:       taskSet([5]int{3, 5, 5, 0, 0}, [5]int{3, 5, 5, 0, 1}, [5]int{2, 10, 10, 0, 0}),
+			priorities:  []int{5, 5, 10},
+			wcrt:        []int{3, 3, 5},
+			schedulable: true,
+		},
+		{
+			name:        "utilization above 1",
+			tasks:       taskSet([5]int{3, 5, 5, 0, 0}, [5]int{3, 5, 10, 0, 0}),
+			priorities:  []int{5, 5},
+			wcrt:        []int{-1, -1},
+			schedulable: false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := ResponseTimeAnalysis(test.tasks, test.priorities)
+			if result.Schedulable != test.schedulable {
+				t.Errorf("schedulable = %v, want %v", result.Schedulable, test.schedulable)
+			}
+			for i, task := range result.Tasks {
+				if task.WCRT != test.wcrt[i] {
+					t.Errorf("WCRT of task %d = %d, want %d", i, task.WCRT, test.wcrt[i])
+				}
+				if want := test.wcrt[i] != -1 && test.wcrt[i] <= test.tasks[i].Deadline; task.Schedulable != want {
+					t.Errorf("task %d schedulable = %v, want %v", i, task.Schedulable, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPriorities(t *testing.T) {
+	tasks := taskSet([5]int{1, 10, 8, 0, 0}, [5]int{5, 20, 7, 0, 0}, [5]int{2, 5, 5, 0, 0})
+	tests := []struct {
+		policy     string
+		priorities []int
+	}{
+		{RM, []int{10, 20, 5}},
+		{DM, []int{8, 7, 5}},
+		{DkC, []int{7, 2, 3}},
+	}
+	for _, test := range tests {
+		t.Run(test.policy, func(t *testing.T) {
+			priorities, err := Priorities(tasks, test.policy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, priority := range priorities {
+				if priority != test.priorities[i] {
+					t.Errorf("priority of task %d = %d, want %d", i, priority, test.priorities[i])
+				}
+			}
+		})
+	}
+
+	if _, err := Priorities(tasks, Explicit); err == nil {
+		t.Error("explicit priorities without the priorities of the tasks should fail")
+	}
+	if _, err := Priorities(tasks, "LLF"); err == nil {
+		t.Error("an unknown policy should fail")
+	}
+}
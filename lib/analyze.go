@@ -0,0 +1,235 @@
+package lib
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"task-generator/lib/analysis"
+	"task-generator/lib/common"
+)
+
+// analysisIndexFile is the name of the summary of the analysis reports in each task set folder
+const analysisIndexFile = "index.analysis.csv"
+
+// AnalysisTests are the names of the schedulability tests of AnalyzeTaskSets:
+// "rta" is the fixed-priority response-time analysis and "qpa" the EDF processor demand analysis of each core, and
+// "gfb", "bcl", "baruah" (G-EDF) and "bc-rta" (G-FP) are the global tests on all the cores. The DAG tests analyze
+// the DAG of the task set instead: "graham" checks each DAG alone on all the cores, "melani" is the G-FP
+// response-time analysis of the DAGs, and "federated" allocates the cores to the DAGs.
+var AnalysisTests = []string{"rta", "qpa", "gfb", "bcl", "baruah", "bc-rta", "graham", "melani", "federated"}
+
+// dagTests are the tests of AnalysisTests that analyze the DAG of a task set
+var dagTests = []string{"graham", "melani", "federated"}
+
+// AnalysisReport is the result of the schedulability analysis of a task set, written next to it
+type AnalysisReport struct {
+	File            string `yaml:"file"`
+	analysis.Result `yaml:",inline"`
+}
+
+// analysisPath returns the path of the analysis report of a task set, e.g., "uniform_0.rta.yaml" for
+// "uniform_0.csv"
+func analysisPath(taskSetPath, test string) string {
+	return taskSetPath[:strings.LastIndex(taskSetPath, ".")] + "." + test + ".yaml"
+}
+
+// AnalyzeTaskSets runs the schedulability tests of each task set in the root folder, and writes a report for each
+// test next to the task set. Each task set folder also gets a summary of all the reports in it. The priority policy
+// of the fixed-priority tests is "RM", "DM", or "explicit" for the priorities in the task sets. The global tests run
+// on the given number of cores, or without it, on the number of cores in the manifest of each task set.
+func AnalyzeTaskSets(root, outputFormat string, tests []string, policy string, cores int) error {
+	for _, test := range tests {
+		if !slices.Contains(AnalysisTests, test) {
+			return fmt.Errorf("unknown schedulability test: %s", test)
+		}
+	}
+	taskSetPaths, err := findTaskSetPaths(root, outputFormat)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	var dirs []string
+	for _, path := range taskSetPaths {
+		if err := analyzeTaskSet(path, outputFormat, tests, policy, cores); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+		}
+		if dir := filepath.Dir(path); len(dirs) == 0 || dirs[len(dirs)-1] != dir {
+			dirs = append(dirs, dir)
+		}
+	}
+	// the summaries are written once all the sets are analyzed
+	for _, dir := range dirs {
+		if err := writeAnalysisIndex(dir); err != nil {
+			errs = append(errs, fmt.Errorf("error writing analysis summary of %s: %w", dir, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// analyzeTaskSet runs the schedulability tests of a task set file and writes their reports
+func analyzeTaskSet(path, outputFormat string, tests []string, policy string, cores int) error {
+	tasks, err := readTaskSet(path, outputFormat)
+	if err != nil {
+		return fmt.Errorf("error reading task set: %w", err)
+	}
+	if cores == 0 {
+		// the sets of a sweep can have different numbers of cores
+		if manifest, err := readManifest(manifestPath(path)); err == nil {
+			cores = manifest.Cores
+		}
+	}
+	var dags []analysis.DAGTask
+	for _, test := range tests {
+		var result analysis.Result
+		if slices.Contains(dagTests, test) {
+			if dags == nil {
+				vertices, err := readVertexSet(precPath(path, outputFormat), outputFormat)
+				if err != nil {
+					return fmt.Errorf("error reading DAG (see generate_dags): %w", err)
+				}
+				dags = analysis.DAGTasks(vertices)
+			}
+			result, err = runDAGTest(tasks, dags, test, policy, cores)
+		} else {
+			result, err = runTest(tasks, test, policy, cores)
+		}
+		if err != nil {
+			return err
+		}
+		data, err := yaml.Marshal(&AnalysisReport{File: filepath.Base(path), Result: result})
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(analysisPath(path, test), data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runTest runs a schedulability test on a task set
+func runTest(tasks common.TaskSet, test, policy string, cores int) (analysis.Result, error) {
+	switch test {
+	case "rta", "bc-rta":
+		if test == "bc-rta" && policy == analysis.OPA {
+			// the OPA priorities are assigned on each core with the uniprocessor analysis
+			return analysis.Result{}, fmt.Errorf("the %s priorities are only defined for the tasks of the "+
+				"response-time analysis", policy)
+		}
+		priorities, err := analysis.Priorities(tasks, policy)
+		if err != nil {
+			return analysis.Result{}, err
+		}
+		var result analysis.Result
+		if test == "rta" {
+			result = analysis.ResponseTimeAnalysis(tasks, priorities)
+		} else {
+			if cores < 1 {
+				return analysis.Result{}, fmt.Errorf("unknown number of cores for the global tests")
+			}
+			result = analysis.GlobalResponseTimeAnalysis(tasks, priorities, cores)
+		}
+		result.Priority = policy
+		return result, nil
+	case "qpa":
+		return analysis.QPA(tasks), nil
+	}
+
+	if cores < 1 {
+		return analysis.Result{}, fmt.Errorf("unknown number of cores for the global tests")
+	}
+	switch test {
+	case "gfb":
+		return analysis.GFB(tasks, cores), nil
+	case "bcl":
+		return analysis.BCL(tasks, cores), nil
+	case "baruah":
+		return analysis.Baruah(tasks, cores), nil
+	}
+	return analysis.Result{}, fmt.Errorf("unknown schedulability test: %s", test)
+}
+
+// runDAGTest runs a schedulability test on the DAG tasks of a task set
+func runDAGTest(tasks common.TaskSet, dags []analysis.DAGTask, test, policy string, cores int) (analysis.Result, error) {
+	if cores < 1 {
+		return analysis.Result{}, fmt.Errorf("unknown number of cores for the DAG tests")
+	}
+	switch test {
+	case "graham":
+		return analysis.Graham(dags, cores), nil
+	case "melani":
+		priorities, err := analysis.DAGPriorities(dags, policy, tasks)
+		if err != nil {
+			return analysis.Result{}, err
+		}
+		result := analysis.MelaniResponseTimeAnalysis(dags, priorities, cores)
+		result.Priority = policy
+		return result, nil
+	case "federated":
+		return analysis.Federated(dags, cores), nil
+	}
+	return analysis.Result{}, fmt.Errorf("unknown schedulability test: %s", test)
+}
+
+// writeAnalysisIndex collects all the analysis reports in a task set folder and writes them to a summary file
+func writeAnalysisIndex(dir string) error {
+	var reports []*AnalysisReport
+	for _, test := range AnalysisTests {
+		paths, err := filepath.Glob(filepath.Join(dir, "*."+test+".yaml"))
+		if err != nil {
+			return err
+		}
+		for _, path := range paths {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			var report AnalysisReport
+			if err := yaml.Unmarshal(data, &report); err != nil {
+				return fmt.Errorf("cannot read analysis report %s: %v", path, err)
+			}
+			reports = append(reports, &report)
+		}
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].File != reports[j].File {
+			return reports[i].File < reports[j].File
+		}
+		return reports[i].Test < reports[j].Test
+	})
+
+	file, err := os.Create(filepath.Join(dir, analysisIndexFile))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	headers := []string{"File", "Test", "Priority", "Cores", "Utilization", "Schedulable"}
+	writer.Write(headers)
+
+	for _, r := range reports {
+		row := []string{
+			r.File,
+			r.Test,
+			r.Priority,
+			strconv.Itoa(r.Processors),
+			strconv.FormatFloat(r.Utilization, 'f', 6, 64),
+			strconv.FormatBool(r.Schedulable),
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package lib
+
+import (
+	"task-generator/lib/analysis"
+	"task-generator/lib/common"
+	"testing"
+)
+
+func TestRunTestGlobalOPA(t *testing.T) {
+	tasks := common.TaskSet{
+		{TaskID: 0, WCET: 1, Period: 4, Deadline: 4},
+		{TaskID: 1, WCET: 2, Period: 6, Deadline: 6},
+	}
+	if _, err := runTest(tasks, "rta", analysis.OPA, 1); err != nil {
+		t.Errorf("rta with OPA: %v", err)
+	}
+	// the OPA priorities are assigned on each core, there is no global OPA
+	if _, err := runTest(tasks, "bc-rta", analysis.OPA, 2); err == nil {
+		t.Error("bc-rta with OPA should fail")
+	}
+	if err := checkFilter(&filter{test: "bc-rta", policy: analysis.OPA}); err == nil {
+		t.Error("a bc-rta filter with OPA should fail")
+	}
+	if err := checkFilter(&filter{test: "bc-rta", policy: analysis.DM}); err != nil {
+		t.Errorf("a bc-rta filter with DM: %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package lib
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// checkArrivalModel checks the arrival model of the jobs and its parameters
+func checkArrivalModel(model string, delay, burstLength float64) error {
+	switch model {
+	case "", "periodic":
+		return nil
+	case "uniform", "exponential":
+	case "bursty":
+		if burstLength < 1 {
+			return fmt.Errorf("the burst length of bursty arrivals should be at least 1")
+		}
+	default:
+		return fmt.Errorf("unknown arrival model: %s", model)
+	}
+	if delay < 0 {
+		return fmt.Errorf("the arrival delay should not be negative")
+	}
+	return nil
+}
+
+// interArrivalDelay draws the delay X that is added to the period T between two releases of a sporadic task:
+// "uniform" X in [0, delay * T], "exponential" X with mean delay * T, and "bursty" X = 0 inside a burst with the
+// mean length of burstLength jobs, and an exponential gap after the burst, so X has the mean delay * T as well
+func interArrivalDelay(rng *rand.Rand, model string, period int, delay, burstLength float64) int {
+	mean := delay * float64(period)
+	switch model {
+	case "uniform":
+		return rng.Intn(int(mean) + 1)
+	case "exponential":
+		return int(rng.ExpFloat64() * mean)
+	case "bursty":
+		if rng.Float64() < 1-1/burstLength {
+			return 0
+		}
+		return int(rng.ExpFloat64() * mean * burstLength)
+	}
+	return 0
+}
+
+// releases returns the release times of the jobs of a task in [0, horizon).
+// Periodic jobs are released every period after the offset, and sporadic jobs at least a period apart.
+func (g *Generator) releases(rng *rand.Rand, period, offset, horizon int) []int {
+	var times []int
+	for release := offset; release < horizon; {
+		times = append(times, release)
+		release += period
+		if g.arrivalModel != "" && g.arrivalModel != "periodic" {
+			release += interArrivalDelay(rng, g.arrivalModel, period, g.arrivalDelay, g.burstLength)
+		}
+	}
+	return times
+}
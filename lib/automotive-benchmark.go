@@ -1,6 +1,7 @@
 package lib
 
 import (
+	"fmt"
 	"math"
 	"math/rand"
 )
@@ -13,13 +14,13 @@ var (
 	wcetFmax     = map[int]float64{1000: 29.11, 2000: 19.04, 5000: 18.44, 10000: 30.03, 20000: 15.61, 50000: 7.76, 100000: 8.88, 200000: 4.90, 1000000: 4.75}
 )
 
-func generateAutomotiveWCET(period int) int {
+func generateAutomotiveWCET(rng *rand.Rand, period int) int {
 	min := acetByPeriod[period] * wcetFmin[period]
 	max := acetByPeriod[period] * wcetFmax[period]
-	return int(min + rand.Float64()*(max-min))
+	return int(min + rng.Float64()*(max-min))
 }
 
-func generateAutomotivePeriod() int {
+func generateAutomotivePeriod(rng *rand.Rand) int {
 	options := []int{1000, 2000, 5000, 10000, 20000, 50000, 100000, 200000, 1000000}
 	weights := []float64{0.04, 0.02, 0.02, 0.29, 0.29, 0.04, 0.24, 0.01, 0.05}
 	var totalWeight float64
@@ -29,11 +30,11 @@ func generateAutomotivePeriod() int {
 	for i, weight := range weights {
 		weights[i] = weight / totalWeight
 	}
-	return options[weightedRandom(weights)]
+	return options[weightedRandom(rng, weights)]
 }
 
-func weightedRandom(weights []float64) int {
-	r := rand.Float64()
+func weightedRandom(rng *rand.Rand, weights []float64) int {
+	r := rng.Float64()
 	for i, weight := range weights {
 		r -= weight
 		if r <= 0 {
@@ -43,13 +44,13 @@ func weightedRandom(weights []float64) int {
 	return len(weights) - 1
 }
 
-func generateAutomotiveRunnable(targetUtilization float64) ([]int, []int) {
+func generateAutomotiveRunnable(rng *rand.Rand, targetUtilization float64) ([]int, []int) {
 	var periods []int
 	var wcets []int
 	currentUtilization := 0.0
 	for math.Abs(currentUtilization-targetUtilization) > 0.01 {
-		period := generateAutomotivePeriod()
-		wcet := generateAutomotiveWCET(period)
+		period := generateAutomotivePeriod(rng)
+		wcet := generateAutomotiveWCET(rng, period)
 		if currentUtilization+float64(wcet)/float64(period) < targetUtilization {
 			currentUtilization += float64(wcet) / float64(period)
 			periods = append(periods, period)
@@ -59,8 +60,8 @@ func generateAutomotiveRunnable(targetUtilization float64) ([]int, []int) {
 	return periods, wcets
 }
 
-func generateAutomotiveTaskSet(targetUtilization float64) [][]int {
-	periods, wcets := generateAutomotiveRunnable(targetUtilization)
+func generateAutomotiveTaskSet(rng *rand.Rand, targetUtilization float64) [][]int {
+	periods, wcets := generateAutomotiveRunnable(rng, targetUtilization)
 	tasks := make([][]int, 0)
 	t1 := periods[0]
 	c1 := 0
@@ -70,7 +71,7 @@ func generateAutomotiveTaskSet(targetUtilization float64) [][]int {
 		}
 		c1 += wcets[i]
 	}
-	ai := rand.Float64() * float64(2*(t1-c1))
+	ai := rng.Float64() * float64(2*(t1-c1))
 	var currentTask []int
 	for i, period := range periods {
 		if period == t1 && currentTask != nil && float64(currentTask[1]+wcets[i]) <= ai {
@@ -80,7 +81,7 @@ func generateAutomotiveTaskSet(targetUtilization float64) [][]int {
 				tasks = append(tasks, currentTask)
 			}
 			currentTask = []int{period, wcets[i]}
-			ai = rand.Float64() * float64(2*(t1-c1))
+			ai = rng.Float64() * float64(2*(t1-c1))
 		}
 	}
 	if currentTask != nil {
@@ -88,3 +89,31 @@ func generateAutomotiveTaskSet(targetUtilization float64) [][]int {
 	}
 	return tasks
 }
+
+// automotiveGenerator is the "automotive" distribution of both utilizations and periods
+type automotiveGenerator struct{}
+
+// Check checks that there is a utilization to fill
+func (automotiveGenerator) Check(p UtilizationParams) error {
+	if err := checkNoTaskBounds(p); err != nil {
+		return err
+	}
+	if len(p.Bounds) > 0 {
+		return fmt.Errorf("the utilization bounds are only valid for rand-fixed-sum and drs utilization distributions")
+	}
+	if p.Utilization <= 0 {
+		return fmt.Errorf("the utilization of the automotive method should be positive")
+	}
+	return nil
+}
+
+// Tasks generates the tasks with the automotive method
+func (automotiveGenerator) Tasks(rng *rand.Rand, p UtilizationParams) ([]float64, []int, error) {
+	var util []float64
+	var periods []int
+	for _, task := range generateAutomotiveTaskSet(rng, p.Utilization) {
+		periods = append(periods, task[0])
+		util = append(util, float64(task[1])/float64(task[0]))
+	}
+	return util, periods, nil
+}
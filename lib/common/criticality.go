@@ -0,0 +1,131 @@
+package common
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// LO is the low criticality level
+	LO = 0
+	// HI is the high criticality level
+	HI = 1
+)
+
+// Budgets returns the WCET of the task at each criticality level up to its own level.
+// A task without mixed-criticality budgets has a single budget, its WCET.
+func (t *Task) Budgets() []int {
+	if len(t.WCETs) == 0 {
+		return []int{t.WCET}
+	}
+	return t.WCETs
+}
+
+// Budgets returns the WCET of the vertex at each criticality level up to its own level, like the budgets of a task
+func (v *Vertex) Budgets() []int {
+	if len(v.WCETs) == 0 {
+		return []int{v.WCET}
+	}
+	return v.WCETs
+}
+
+// IsMixedCriticality reports whether a task of the task set has a higher criticality than LO
+func (ts TaskSet) IsMixedCriticality() bool {
+	for _, t := range ts {
+		if t.Criticality > LO {
+			return true
+		}
+	}
+	return false
+}
+
+// isMixedCriticality reports whether a vertex of the vertex set has a higher criticality than LO
+func (vs VertexSet) isMixedCriticality() bool {
+	for _, vertex := range vs {
+		if vertex.Criticality > LO {
+			return true
+		}
+	}
+	return false
+}
+
+// IsMixedCriticality reports whether a job of the job set has a higher criticality than LO
+func (js JobSet) IsMixedCriticality() bool {
+	for _, job := range js {
+		if job.criticality() > LO {
+			return true
+		}
+	}
+	return false
+}
+
+// criticality returns the criticality of the task or the vertex of the job
+func (job *Job) criticality() int {
+	if job.Vertex != nil {
+		return job.Vertex.Criticality
+	}
+	return job.Task.Criticality
+}
+
+// budgets returns the budgets of the task or the vertex of the job
+func (job *Job) budgets() []int {
+	if job.Vertex != nil {
+		return job.Vertex.Budgets()
+	}
+	return job.Task.Budgets()
+}
+
+// intListString writes a list of integers as "[1,2,3]"
+func intListString(values []int) string {
+	str := "["
+	for _, value := range values {
+		str += strconv.Itoa(value) + ","
+	}
+	if len(str) > 1 {
+		str = str[:len(str)-1]
+	}
+	return str + "]"
+}
+
+// parseIntList reads a list of integers written as "[1,2,3]"
+func parseIntList(str string) []int {
+	str = strings.TrimSpace(str)
+	str = strings.TrimPrefix(strings.TrimSuffix(str, "]"), "[")
+	var values []int
+	if len(strings.TrimSpace(str)) == 0 {
+		return values
+	}
+	for _, value := range strings.Split(str, ",") {
+		temp, _ := strconv.Atoi(strings.TrimSpace(value))
+		values = append(values, temp)
+	}
+	return values
+}
+
+// yamlIntList reads a list of integers of a YAML file, or nil if there is none
+func yamlIntList(value interface{}) []int {
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+	var values []int
+	for _, v := range list {
+		temp, _ := v.(int)
+		values = append(values, temp)
+	}
+	return values
+}
+
+// yamlInts reads the integers of the required keys of an element of a YAML file
+func yamlInts(fields map[string]interface{}, keys ...string) ([]int, error) {
+	values := make([]int, len(keys))
+	for i, key := range keys {
+		value, ok := fields[key].(int)
+		if !ok {
+			return nil, fmt.Errorf("missing or invalid %s", key)
+		}
+		values[i] = value
+	}
+	return values, nil
+}
@@ -0,0 +1,62 @@
+package common
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// csvColumns maps the names of the columns of a CSV header to their indices, so files with and without the optional
+// columns can be read
+type csvColumns map[string]int
+
+// newCSVColumns reads the columns of a CSV header
+func newCSVColumns(header []string) csvColumns {
+	columns := make(csvColumns, len(header))
+	for i, name := range header {
+		columns[name] = i
+	}
+	return columns
+}
+
+// require checks that the header has all the required columns
+func (c csvColumns) require(names ...string) error {
+	for _, name := range names {
+		if _, ok := c[name]; !ok {
+			return fmt.Errorf("no %q column", name)
+		}
+	}
+	return nil
+}
+
+// field returns the value of a column of a record, or "" if the file has no such column
+func (c csvColumns) field(record []string, name string) string {
+	if i, ok := c[name]; ok && i < len(record) {
+		return record[i]
+	}
+	return ""
+}
+
+// int returns the integer value of a column of a record, or 0 if the file has no such column
+func (c csvColumns) int(record []string, name string) (int, error) {
+	if _, ok := c[name]; !ok {
+		return 0, nil
+	}
+	value, err := strconv.Atoi(c.field(record, name))
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %q", name, c.field(record, name))
+	}
+	return value, nil
+}
+
+// ints returns the integer values of the columns of a record, like int
+func (c csvColumns) ints(record []string, names ...string) ([]int, error) {
+	values := make([]int, len(names))
+	for i, name := range names {
+		value, err := c.int(record, name)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
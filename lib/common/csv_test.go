@@ -0,0 +1,72 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeFile writes a file in a temporary folder of the test and returns its path
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadTaskSet(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		valid   bool
+	}{
+		{"required columns", "TaskID,Jitter,BCET,WCET,Period,Deadline,PE\n0,1,2,3,10,9,1\n", true},
+		{"optional columns", "TaskID,Jitter,BCET,WCET,Period,Deadline,PE,Offset,Priority\n0,1,2,3,10,9,1,4,2\n", true},
+		{"missing period", "TaskID,Jitter,BCET,WCET,Deadline,PE\n0,1,2,3,9,1\n", false},
+		{"invalid WCET", "TaskID,Jitter,BCET,WCET,Period,Deadline,PE\n0,1,2,x,10,9,1\n", false},
+		{"empty deadline", "TaskID,Jitter,BCET,WCET,Period,Deadline,PE\n0,1,2,3,10,,1\n", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tasks, err := ReadTaskSet(writeFile(t, "taskset.csv", test.content))
+			if (err == nil) != test.valid {
+				t.Fatalf("ReadTaskSet() = %v, valid: %v", err, test.valid)
+			}
+			if !test.valid {
+				return
+			}
+			task := tasks[0]
+			if task.Jitter != 1 || task.BCET != 2 || task.WCET != 3 || task.Period != 10 || task.Deadline != 9 ||
+				task.PE != 1 {
+				t.Errorf("read %+v", *task)
+			}
+		})
+	}
+}
+
+func TestReadVertexSet(t *testing.T) {
+	header := "Task ID,Vertex ID,Jitter,BCET,WCET,Period,Deadline,PE,Successors\n"
+	tests := []struct {
+		name    string
+		content string
+		valid   bool
+	}{
+		{"required columns", header + "0,0,0,1,2,10,10,0,\"[1,2]\"\n0,1,0,1,2,10,10,0,[]\n", true},
+		{"missing successors", "Task ID,Vertex ID,Jitter,BCET,WCET,Period,Deadline,PE\n0,0,0,1,2,10,10,0\n", false},
+		{"missing vertex ID", "Task ID,Jitter,BCET,WCET,Period,Deadline,PE,Successors\n0,0,1,2,10,10,0,[]\n", false},
+		{"invalid successor", header + "0,0,0,1,2,10,10,0,\"[1,a]\"\n", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			vertices, err := ReadVertexSet(writeFile(t, "taskset.prec.csv", test.content))
+			if (err == nil) != test.valid {
+				t.Fatalf("ReadVertexSet() = %v, valid: %v", err, test.valid)
+			}
+			if test.valid && (len(vertices[0].Successors) != 2 || len(vertices[1].Successors) != 0) {
+				t.Errorf("successors %v and %v", vertices[0].Successors, vertices[1].Successors)
+			}
+		})
+	}
+}
@@ -3,7 +3,9 @@ package common
 import (
 	"encoding/csv"
 	"fmt"
+	"gopkg.in/yaml.v2"
 	"os"
+	"slices"
 	"strconv"
 )
 
@@ -16,10 +18,29 @@ type Job struct {
 	LatestArrivalTime   int
 	Priority            int
 	AbsoluteDeadline    int
+	// Successors are the indices of the jobs that wait for this job, which are given by the job sets that are read
+	// from files (see Dependencies)
+	Successors []int
 }
 
 type JobSet []*Job
 
+// Costs returns the best-case and the worst-case execution time of the job
+func (job *Job) Costs() (int, int) {
+	if job.Vertex != nil {
+		return job.Vertex.BCET, job.Vertex.WCET
+	}
+	return job.Task.BCET, job.Task.WCET
+}
+
+// PE returns the core of the task or the vertex of the job
+func (job *Job) PE() int {
+	if job.Vertex != nil {
+		return job.Vertex.PE
+	}
+	return job.Task.PE
+}
+
 // WriteJobSet writes a job set to a file
 func (js JobSet) WriteJobSet(path string) error {
 	file, err := os.Create(path)
@@ -32,6 +53,12 @@ func (js JobSet) WriteJobSet(path string) error {
 	defer writer.Flush()
 
 	headers := []string{"Task ID", "Job ID", "Arrival min", "Arrival max", "Cost min", "Cost max", "Deadline", "Priority"}
+	// the budgets of the criticality levels are only added for mixed-criticality job sets, so the other job sets
+	// keep the format of the schedulability analysis tools
+	mixedCriticality := js.IsMixedCriticality()
+	if mixedCriticality {
+		headers = append(headers, "Criticality", "WCETs")
+	}
 	writer.Write(headers)
 
 	for _, job := range js {
@@ -58,6 +85,9 @@ func (js JobSet) WriteJobSet(path string) error {
 			strconv.Itoa(job.AbsoluteDeadline),
 			strconv.Itoa(job.Priority),
 		}...)
+		if mixedCriticality {
+			row = append(row, strconv.Itoa(job.criticality()), intListString(job.budgets()))
+		}
 
 		if err := writer.Write(row); err != nil {
 			return err
@@ -66,12 +96,69 @@ func (js JobSet) WriteJobSet(path string) error {
 	return nil
 }
 
+// Dependencies returns the indices of the successors of each job. A job of a vertex precedes the jobs of the
+// successors of the vertex with the same absolute deadline. When the deadline of a task is larger than its period, its
+// jobs overlap, so each job also precedes the next job of the same task.
+func (js JobSet) Dependencies() [][]int {
+	successors := make([][]int, len(js))
+
+	// the jobs are found by their task and their absolute deadline
+	type key struct{ taskID, deadline int }
+	byDeadline := make(map[key][]int)
+	for i, job := range js {
+		k := key{job.TaskID, job.AbsoluteDeadline}
+		byDeadline[k] = append(byDeadline[k], i)
+	}
+	for i, job := range js {
+		if job.Vertex == nil {
+			continue
+		}
+		for _, successor := range job.Vertex.Successors {
+			for _, j := range byDeadline[key{successor, job.AbsoluteDeadline}] {
+				if j != i {
+					successors[i] = append(successors[i], j)
+				}
+			}
+		}
+	}
+
+	// the jobs of each task are in the order of their release
+	last := make(map[int]int)
+	for i, job := range js {
+		if previous, ok := last[job.TaskID]; ok && js[previous].AbsoluteDeadline > job.EarliestArrivalTime {
+			successors[previous] = append(successors[previous], i)
+		}
+		last[job.TaskID] = i
+	}
+
+	// the job sets that are read from files give the successors of their jobs
+	for i, job := range js {
+		for _, j := range job.Successors {
+			if !slices.Contains(successors[i], j) {
+				successors[i] = append(successors[i], j)
+			}
+		}
+	}
+	return successors
+}
+
+// HasDependencies reports whether a job of the job set has to wait for another job
+func (js JobSet) HasDependencies() bool {
+	for _, successors := range js.Dependencies() {
+		if len(successors) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // WriteDependencyJobSet writes a job set dependency to a file
 func (js JobSet) WriteDependencyJobSet(path string) error {
 	file, err := os.Create(path)
 	if err != nil {
 		return err
 	}
+	defer file.Close()
 
 	// write the dependency job set to a file
 	writer := csv.NewWriter(file)
@@ -80,26 +167,11 @@ func (js JobSet) WriteDependencyJobSet(path string) error {
 	headers := []string{"From TID", "From JID", "To TID", "To JID"}
 	writer.Write(headers)
 
-	for _, job := range js {
-		// find the successor
-		// and keep their job ID
-		var successorIndex []int
-
-		for _, successor := range job.Vertex.Successors {
-			for i, tempJob := range js {
-				if tempJob.JobID == job.JobID {
-					continue
-				}
-				// we need to check if they belong to the same task, and they release at the same time
-				if tempJob.TaskID == successor && tempJob.AbsoluteDeadline == job.AbsoluteDeadline {
-					successorIndex = append(successorIndex, i)
-				}
-			}
-		}
-		for _, successor := range successorIndex {
+	for i, successors := range js.Dependencies() {
+		for _, successor := range successors {
 			row := []string{
-				strconv.Itoa(job.TaskID),
-				strconv.Itoa(job.JobID),
+				strconv.Itoa(js[i].TaskID),
+				strconv.Itoa(js[i].JobID),
 				strconv.Itoa(js[successor].TaskID),
 				strconv.Itoa(js[successor].JobID),
 			}
@@ -118,6 +190,7 @@ func (js JobSet) WriteJobSetYAML(path string) error {
 	if err != nil {
 		return err
 	}
+	defer file.Close()
 	// we need to add vertexset as the root element
 	_, err = file.WriteString("jobset:\n")
 	if err != nil {
@@ -125,7 +198,9 @@ func (js JobSet) WriteJobSetYAML(path string) error {
 	}
 
 	// then, we add the jobs
-	for _, job := range js {
+	mixedCriticality := js.IsMixedCriticality()
+	dependencies := js.Dependencies()
+	for i, job := range js {
 		_, err = file.WriteString(fmt.Sprintf("  - TaskID: %d\n", job.TaskID))
 		_, err = file.WriteString(fmt.Sprintf("    JobID: %d\n", job.JobID))
 		_, err = file.WriteString(fmt.Sprintf("    Arrival min: %d\n", job.EarliestArrivalTime))
@@ -139,24 +214,14 @@ func (js JobSet) WriteJobSetYAML(path string) error {
 		}
 		_, err = file.WriteString(fmt.Sprintf("    Deadline: %d\n", job.AbsoluteDeadline))
 		_, err = file.WriteString(fmt.Sprintf("    Priority: %d\n", job.Priority))
+		if mixedCriticality {
+			_, err = file.WriteString(fmt.Sprintf("    Criticality: %d\n", job.criticality()))
+			_, err = file.WriteString(fmt.Sprintf("    WCETs: %s\n", intListString(job.budgets())))
+		}
 
-		if job.Vertex != nil {
-			// now we need to check if the job has dependencies
-			// find the successor
-			// and keep their job ID
-			var successorIndex []int
-
-			for _, successor := range job.Vertex.Successors {
-				for i, tempJob := range js {
-					if tempJob.JobID == job.JobID {
-						continue
-					}
-					// we need to check if they belong to the same task, and they release at the same time
-					if tempJob.TaskID == successor && tempJob.AbsoluteDeadline == job.AbsoluteDeadline {
-						successorIndex = append(successorIndex, i)
-					}
-				}
-			}
+		// the jobs of vertices always list their successors, the other jobs only if they have any
+		if job.Vertex != nil || len(dependencies[i]) > 0 {
+			successorIndex := dependencies[i]
 			successors := "["
 			for _, successor := range successorIndex {
 				successors += "[" + strconv.Itoa(js[successor].TaskID) + "," + strconv.Itoa(js[successor].JobID) + "],"
@@ -174,3 +239,174 @@ func (js JobSet) WriteJobSetYAML(path string) error {
 	return nil
 
 }
+
+// ReadJobSet reads a job set from a CSV file, like WriteJobSet writes it. The task of each job only has the costs and
+// the budgets of the job. The dependencies of the ".prec" file of the job set are read by ReadDependencyJobSet.
+func ReadJobSet(path string) (JobSet, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+
+	// the criticality columns are only written for mixed-criticality job sets
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the header of %s: %v", path, err)
+	}
+	columns := newCSVColumns(header)
+	if err := columns.require("Task ID", "Job ID", "Arrival min", "Arrival max", "Cost min", "Cost max", "Deadline",
+		"Priority"); err != nil {
+		return nil, fmt.Errorf("invalid job set %s: %v", path, err)
+	}
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs JobSet
+	for _, record := range records {
+		values, err := columns.ints(record, "Task ID", "Job ID", "Arrival min", "Arrival max", "Cost min",
+			"Cost max", "Deadline", "Priority", "Criticality")
+		if err != nil {
+			return nil, fmt.Errorf("invalid job in %s: %v", path, err)
+		}
+		jobs = append(jobs, readJob(values[0], values[1], values[2], values[3], values[4], values[5], values[6],
+			values[7], values[8], parseIntList(columns.field(record, "WCETs"))))
+	}
+	return jobs, nil
+}
+
+// ReadDependencyJobSet reads the dependencies of the jobs of a job set from a CSV file, like WriteDependencyJobSet
+// writes them
+func (js JobSet) ReadDependencyJobSet(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("cannot read the header of %s: %v", path, err)
+	}
+	columns := newCSVColumns(header)
+	if err := columns.require("From TID", "From JID", "To TID", "To JID"); err != nil {
+		return fmt.Errorf("invalid dependencies %s: %v", path, err)
+	}
+	records, err := reader.ReadAll()
+	if err != nil {
+		return err
+	}
+
+	// the dependencies are grouped by their first job
+	successors := make([][][2]int, len(js))
+	index := make(map[[2]int]int, len(js))
+	for i, job := range js {
+		index[[2]int{job.TaskID, job.JobID}] = i
+	}
+	for _, record := range records {
+		values, err := columns.ints(record, "From TID", "From JID", "To TID", "To JID")
+		if err != nil {
+			return fmt.Errorf("invalid dependency in %s: %v", path, err)
+		}
+		from := [2]int{values[0], values[1]}
+		i, ok := index[from]
+		if !ok {
+			return fmt.Errorf("invalid dependency in %s: unknown job %d of task %d", path, from[1], from[0])
+		}
+		successors[i] = append(successors[i], [2]int{values[2], values[3]})
+	}
+	if err := js.setSuccessors(successors); err != nil {
+		return fmt.Errorf("invalid dependency in %s: %v", path, err)
+	}
+	return nil
+}
+
+// ReadJobSetYAML reads a job set from a YAML file, like WriteJobSetYAML writes it, with the successors of its jobs
+func ReadJobSetYAML(path string) (JobSet, error) {
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var jobSet map[string][]map[string]interface{}
+	if err := yaml.Unmarshal(file, &jobSet); err != nil {
+		return nil, err
+	}
+
+	var jobs JobSet
+	var successors [][][2]int
+	for _, j := range jobSet["jobset"] {
+		values, err := yamlInts(j, "TaskID", "JobID", "Arrival min", "Arrival max", "Cost min", "Cost max",
+			"Deadline", "Priority")
+		if err != nil {
+			return nil, fmt.Errorf("invalid job in %s: %v", path, err)
+		}
+		criticality, _ := j["Criticality"].(int)
+		jobs = append(jobs, readJob(values[0], values[1], values[2], values[3], values[4], values[5], values[6],
+			values[7], criticality, yamlIntList(j["WCETs"])))
+
+		// the successors are listed as [task ID, job ID] pairs
+		list, _ := j["Successors"].([]interface{})
+		var jobSuccessors [][2]int
+		for _, successor := range list {
+			pair := yamlIntList(successor)
+			if len(pair) != 2 {
+				return nil, fmt.Errorf("invalid job in %s: invalid successor %v", path, successor)
+			}
+			jobSuccessors = append(jobSuccessors, [2]int{pair[0], pair[1]})
+		}
+		successors = append(successors, jobSuccessors)
+	}
+	if err := jobs.setSuccessors(successors); err != nil {
+		return nil, fmt.Errorf("invalid job set %s: %v", path, err)
+	}
+	return jobs, nil
+}
+
+// readJob returns a job of a job set file, whose task only has the costs and the budgets of the job
+func readJob(taskID, jobID, arrivalMin, arrivalMax, costMin, costMax, deadline, priority, criticality int,
+	wcets []int) *Job {
+	return &Job{
+		Task: &Task{
+			TaskID:      taskID,
+			BCET:        costMin,
+			WCET:        costMax,
+			Criticality: criticality,
+			WCETs:       wcets,
+		},
+		TaskID:              taskID,
+		JobID:               jobID,
+		EarliestArrivalTime: arrivalMin,
+		LatestArrivalTime:   arrivalMax,
+		Priority:            priority,
+		AbsoluteDeadline:    deadline,
+	}
+}
+
+// setSuccessors sets the successors of the jobs, given by the [task ID, job ID] of the successors of each job
+func (js JobSet) setSuccessors(successors [][][2]int) error {
+	index := make(map[[2]int]int, len(js))
+	for i, job := range js {
+		index[[2]int{job.TaskID, job.JobID}] = i
+	}
+	for i, jobSuccessors := range successors {
+		for _, successor := range jobSuccessors {
+			j, ok := index[successor]
+			if !ok {
+				return fmt.Errorf("job %d of task %d has the unknown successor %d of task %d", js[i].JobID,
+					js[i].TaskID, successor[1], successor[0])
+			}
+			js[i].Successors = append(js[i].Successors, j)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,301 @@
+package common
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// JSONVersion is the version of the JSON files, and of their schemas in the schemas folder
+const JSONVersion = 1
+
+// the JSON schemas of the files, e.g., "taskset.v1.json"
+//
+//go:embed schemas/*.json
+var schemas embed.FS
+
+// WriteSchemas writes the JSON schemas of the task sets, the vertex sets, and the job sets to a folder
+func WriteSchemas(dir string) error {
+	entries, err := schemas.ReadDir("schemas")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		data, err := schemas.ReadFile("schemas/" + entry.Name())
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, entry.Name()), data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// taskJSON is a task of a JSON file
+type taskJSON struct {
+	TaskID      int   `json:"task_id"`
+	Jitter      int   `json:"jitter"`
+	BCET        int   `json:"bcet"`
+	WCET        int   `json:"wcet"`
+	Period      int   `json:"period"`
+	Deadline    int   `json:"deadline"`
+	PE          int   `json:"pe"`
+	Offset      int   `json:"offset"`
+	Criticality int   `json:"criticality"`
+	WCETs       []int `json:"wcets"`
+	Priority    int   `json:"priority"`
+}
+
+// vertexJSON is a vertex of a JSON file
+type vertexJSON struct {
+	TaskID      int   `json:"task_id"`
+	VertexID    int   `json:"vertex_id"`
+	Jitter      int   `json:"jitter"`
+	BCET        int   `json:"bcet"`
+	WCET        int   `json:"wcet"`
+	Period      int   `json:"period"`
+	Deadline    int   `json:"deadline"`
+	PE          int   `json:"pe"`
+	Successors  []int `json:"successors"`
+	Offset      int   `json:"offset"`
+	Criticality int   `json:"criticality"`
+	WCETs       []int `json:"wcets"`
+}
+
+// jobJSON is a job of a JSON file, whose successors are given by their [task ID, job ID]
+type jobJSON struct {
+	TaskID      int      `json:"task_id"`
+	JobID       int      `json:"job_id"`
+	ArrivalMin  int      `json:"arrival_min"`
+	ArrivalMax  int      `json:"arrival_max"`
+	CostMin     int      `json:"cost_min"`
+	CostMax     int      `json:"cost_max"`
+	Deadline    int      `json:"deadline"`
+	Priority    int      `json:"priority"`
+	Criticality int      `json:"criticality"`
+	WCETs       []int    `json:"wcets"`
+	Successors  [][2]int `json:"successors"`
+}
+
+// the JSON files, with the format and the version of their schema
+type taskSetJSON struct {
+	Format  string     `json:"format"`
+	Version int        `json:"version"`
+	Tasks   []taskJSON `json:"tasks"`
+}
+
+type vertexSetJSON struct {
+	Format   string       `json:"format"`
+	Version  int          `json:"version"`
+	Vertices []vertexJSON `json:"vertices"`
+}
+
+type jobSetJSON struct {
+	Format  string    `json:"format"`
+	Version int       `json:"version"`
+	Jobs    []jobJSON `json:"jobs"`
+}
+
+// writeJSON writes a value to an indented JSON file
+func writeJSON(path string, value interface{}) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// checkJSONFormat checks the format and the version of a JSON file
+func checkJSONFormat(path, format string, wantFormat string, version int) error {
+	if format != wantFormat {
+		return fmt.Errorf("%s is not a %s file", path, wantFormat)
+	}
+	if version < 1 || version > JSONVersion {
+		return fmt.Errorf("%s has the unsupported version %d of the %s format", path, version, wantFormat)
+	}
+	return nil
+}
+
+// WriteTaskSetJSON writes a task set to a JSON file, like WriteTaskSet
+func (ts TaskSet) WriteTaskSetJSON(path string) error {
+	file := taskSetJSON{Format: "taskset", Version: JSONVersion, Tasks: make([]taskJSON, 0, len(ts))}
+	for i, t := range ts {
+		file.Tasks = append(file.Tasks, taskJSON{
+			TaskID:      i,
+			Jitter:      t.Jitter,
+			BCET:        t.BCET,
+			WCET:        t.WCET,
+			Period:      t.Period,
+			Deadline:    t.Deadline,
+			PE:          t.PE,
+			Offset:      t.Offset,
+			Criticality: t.Criticality,
+			WCETs:       t.Budgets(),
+			Priority:    t.Priority,
+		})
+	}
+	return writeJSON(path, file)
+}
+
+// ReadTaskSetJSON reads a task set from a JSON file
+func ReadTaskSetJSON(path string) (TaskSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file taskSetJSON
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("cannot read %s: %v", path, err)
+	}
+	if err := checkJSONFormat(path, file.Format, "taskset", file.Version); err != nil {
+		return nil, err
+	}
+	if err := validateJSON(data, fmt.Sprintf("taskset.v%d.json", file.Version)); err != nil {
+		return nil, fmt.Errorf("invalid task set %s: %v", path, err)
+	}
+
+	var tasks TaskSet
+	for _, t := range file.Tasks {
+		tasks = append(tasks, &Task{
+			TaskID:      t.TaskID,
+			Jitter:      t.Jitter,
+			BCET:        t.BCET,
+			WCET:        t.WCET,
+			Period:      t.Period,
+			Deadline:    t.Deadline,
+			PE:          t.PE,
+			Offset:      t.Offset,
+			Criticality: t.Criticality,
+			WCETs:       t.WCETs,
+			Priority:    t.Priority,
+		})
+	}
+	return tasks, nil
+}
+
+// WriteVertexSetJSON writes a vertex set to a JSON file, like WriteVertexSet
+func (vs VertexSet) WriteVertexSetJSON(path string) error {
+	file := vertexSetJSON{Format: "vertexset", Version: JSONVersion, Vertices: make([]vertexJSON, 0, len(vs))}
+	for _, vertex := range vs {
+		successors := vertex.Successors
+		if successors == nil {
+			successors = []int{}
+		}
+		file.Vertices = append(file.Vertices, vertexJSON{
+			TaskID:      vertex.TaskID,
+			VertexID:    vertex.VertexID,
+			Jitter:      vertex.Jitter,
+			BCET:        vertex.BCET,
+			WCET:        vertex.WCET,
+			Period:      vertex.Period,
+			Deadline:    vertex.Deadline,
+			PE:          vertex.PE,
+			Successors:  successors,
+			Offset:      vertex.Offset,
+			Criticality: vertex.Criticality,
+			WCETs:       vertex.Budgets(),
+		})
+	}
+	return writeJSON(path, file)
+}
+
+// ReadVertexSetJSON reads a vertex set from a JSON file
+func ReadVertexSetJSON(path string) (VertexSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file vertexSetJSON
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("cannot read %s: %v", path, err)
+	}
+	if err := checkJSONFormat(path, file.Format, "vertexset", file.Version); err != nil {
+		return nil, err
+	}
+	if err := validateJSON(data, fmt.Sprintf("vertexset.v%d.json", file.Version)); err != nil {
+		return nil, fmt.Errorf("invalid vertex set %s: %v", path, err)
+	}
+
+	var vertices VertexSet
+	for _, vertex := range file.Vertices {
+		vertices = append(vertices, &Vertex{
+			TaskID:      vertex.TaskID,
+			VertexID:    vertex.VertexID,
+			Jitter:      vertex.Jitter,
+			BCET:        vertex.BCET,
+			WCET:        vertex.WCET,
+			Period:      vertex.Period,
+			Deadline:    vertex.Deadline,
+			PE:          vertex.PE,
+			Offset:      vertex.Offset,
+			Criticality: vertex.Criticality,
+			WCETs:       vertex.WCETs,
+			Successors:  vertex.Successors,
+		})
+	}
+	return vertices, nil
+}
+
+// WriteJobSetJSON writes a job set to a JSON file, with the successors of each job (see Dependencies)
+func (js JobSet) WriteJobSetJSON(path string) error {
+	file := jobSetJSON{Format: "jobset", Version: JSONVersion, Jobs: make([]jobJSON, 0, len(js))}
+	for i, successors := range js.Dependencies() {
+		job := js[i]
+		bcet, wcet := job.Costs()
+		successorIDs := make([][2]int, 0, len(successors))
+		for _, successor := range successors {
+			successorIDs = append(successorIDs, [2]int{js[successor].TaskID, js[successor].JobID})
+		}
+		file.Jobs = append(file.Jobs, jobJSON{
+			TaskID:      job.TaskID,
+			JobID:       job.JobID,
+			ArrivalMin:  job.EarliestArrivalTime,
+			ArrivalMax:  job.LatestArrivalTime,
+			CostMin:     bcet,
+			CostMax:     wcet,
+			Deadline:    job.AbsoluteDeadline,
+			Priority:    job.Priority,
+			Criticality: job.criticality(),
+			WCETs:       job.budgets(),
+			Successors:  successorIDs,
+		})
+	}
+	return writeJSON(path, file)
+}
+
+// ReadJobSetJSON reads a job set from a JSON file, which is checked against the schema of its version. The task of
+// each job only has the costs and the budgets of the job.
+func ReadJobSetJSON(path string) (JobSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file jobSetJSON
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("cannot read %s: %v", path, err)
+	}
+	if err := checkJSONFormat(path, file.Format, "jobset", file.Version); err != nil {
+		return nil, err
+	}
+	if err := validateJSON(data, fmt.Sprintf("jobset.v%d.json", file.Version)); err != nil {
+		return nil, fmt.Errorf("invalid job set %s: %v", path, err)
+	}
+
+	jobs := make(JobSet, 0, len(file.Jobs))
+	successors := make([][][2]int, 0, len(file.Jobs))
+	for _, job := range file.Jobs {
+		jobs = append(jobs, readJob(job.TaskID, job.JobID, job.ArrivalMin, job.ArrivalMax, job.CostMin, job.CostMax,
+			job.Deadline, job.Priority, job.Criticality, job.WCETs))
+		successors = append(successors, job.Successors)
+	}
+	if err := jobs.setSuccessors(successors); err != nil {
+		return nil, fmt.Errorf("invalid job set %s: %v", path, err)
+	}
+	return jobs, nil
+}
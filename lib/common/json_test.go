@@ -0,0 +1,159 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTaskSetJSON(t *testing.T) {
+	tasks := TaskSet{
+		{TaskID: 0, Jitter: 1, BCET: 2, WCET: 3, Period: 10, Deadline: 9, PE: 1},
+		{TaskID: 1, BCET: 1, WCET: 4, Period: 20, Deadline: 20, Offset: 5, Criticality: 1, WCETs: []int{4, 8},
+			Priority: 2},
+	}
+	path := filepath.Join(t.TempDir(), "taskset.json")
+	if err := tasks.WriteTaskSetJSON(path); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadTaskSetJSON(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the budgets of a LO task are written as its WCET
+	want := []Task{*tasks[0], *tasks[1]}
+	want[0].WCETs = []int{3}
+	for i, task := range read {
+		if !reflect.DeepEqual(*task, want[i]) {
+			t.Errorf("task %+v, want %+v", *task, want[i])
+		}
+	}
+}
+
+func TestVertexSetJSON(t *testing.T) {
+	vertices := VertexSet{
+		{TaskID: 0, VertexID: 0, BCET: 1, WCET: 2, Period: 10, Deadline: 10, Successors: []int{1, 2}},
+		{TaskID: 0, VertexID: 1, BCET: 1, WCET: 3, Period: 10, Deadline: 10, Successors: []int{2}},
+		{TaskID: 0, VertexID: 2, BCET: 2, WCET: 2, Period: 10, Deadline: 10, PE: 1, Successors: []int{}},
+	}
+	path := filepath.Join(t.TempDir(), "taskset.prec.json")
+	if err := vertices.WriteVertexSetJSON(path); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadVertexSetJSON(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, vertex := range read {
+		want := *vertices[i]
+		want.WCETs = []int{want.WCET}
+		if !reflect.DeepEqual(*vertex, want) {
+			t.Errorf("vertex %+v, want %+v", *vertex, want)
+		}
+	}
+}
+
+func TestJobSetJSON(t *testing.T) {
+	content := `{"format": "jobset", "version": 1, "jobs": [
+		{"task_id": 0, "job_id": 0, "arrival_min": 0, "arrival_max": 1, "cost_min": 1, "cost_max": 2,
+			"deadline": 10, "priority": 10, "successors": [[1, 0]]},
+		{"task_id": 1, "job_id": 0, "arrival_min": 0, "arrival_max": 0, "cost_min": 2, "cost_max": 3,
+			"deadline": 20, "priority": 20, "criticality": 1, "wcets": [3, 6], "successors": []},
+		{"task_id": 0, "job_id": 1, "arrival_min": 10, "arrival_max": 11, "cost_min": 1, "cost_max": 2,
+			"deadline": 20, "priority": 10, "successors": [[1, 0]]}
+	]}`
+	jobs, err := ReadJobSetJSON(writeFile(t, "jobset.json", content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 3 || !reflect.DeepEqual(jobs[0].Successors, []int{1}) || len(jobs[1].Successors) != 0 ||
+		!reflect.DeepEqual(jobs[2].Successors, []int{1}) {
+		t.Fatalf("the successors of the jobs are not read")
+	}
+	if bcet, wcet := jobs[1].Costs(); bcet != 2 || wcet != 3 || jobs[1].Task.Criticality != 1 ||
+		!reflect.DeepEqual(jobs[1].Task.WCETs, []int{3, 6}) {
+		t.Errorf("job %+v of task %+v", *jobs[1], *jobs[1].Task)
+	}
+
+	// the written job set is read back the same
+	dir := t.TempDir()
+	first, second := filepath.Join(dir, "first.json"), filepath.Join(dir, "second.json")
+	if err := jobs.WriteJobSetJSON(first); err != nil {
+		t.Fatal(err)
+	}
+	if jobs, err = ReadJobSetJSON(first); err != nil {
+		t.Fatal(err)
+	}
+	if err := jobs.WriteJobSetJSON(second); err != nil {
+		t.Fatal(err)
+	}
+	a, _ := os.ReadFile(first)
+	b, _ := os.ReadFile(second)
+	if string(a) != string(b) {
+		t.Errorf("the job set changes when it is read back:\n%s\n%s", a, b)
+	}
+}
+
+func TestValidateJSON(t *testing.T) {
+	job := `{"task_id": 0, "job_id": 0, "arrival_min": 0, "arrival_max": 0, "cost_min": 1, "cost_max": 2,
+		"deadline": 10, "priority": 1, "successors": %s}`
+	jobSet := func(jobs ...string) string {
+		return `{"format": "jobset", "version": 1, "jobs": [` + strings.Join(jobs, ",") + `]}`
+	}
+	withSuccessors := func(successors string) string {
+		return strings.Replace(job, "%s", successors, 1)
+	}
+	tests := []struct {
+		name    string
+		content string
+		valid   bool
+	}{
+		{"valid", jobSet(withSuccessors("[]")), true},
+		{"no jobs", jobSet(), true},
+		{"wrong format", strings.Replace(jobSet(), "jobset", "taskset", 1), false},
+		{"unknown version", strings.Replace(jobSet(), `"version": 1`, `"version": 2`, 1), false},
+		{"missing key", jobSet(strings.Replace(withSuccessors("[]"), `"priority": 1,`, "", 1)), false},
+		{"unknown key", jobSet(strings.Replace(withSuccessors("[]"), `"priority": 1`, `"priority": 1, "x": 1`, 1)),
+			false},
+		{"negative cost", jobSet(strings.Replace(withSuccessors("[]"), `"cost_min": 1`, `"cost_min": -1`, 1)), false},
+		{"fractional cost", jobSet(strings.Replace(withSuccessors("[]"), `"cost_max": 2`, `"cost_max": 2.5`, 1)),
+			false},
+		{"string cost", jobSet(strings.Replace(withSuccessors("[]"), `"cost_max": 2`, `"cost_max": "2"`, 1)), false},
+		{"successor of three IDs", jobSet(withSuccessors("[[0, 0, 0]]")), false},
+		{"unknown successor", jobSet(withSuccessors("[[3, 0]]")), false},
+		{"not JSON", "jobs: []", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := ReadJobSetJSON(writeFile(t, "jobset.json", test.content)); (err == nil) != test.valid {
+				t.Errorf("ReadJobSetJSON() = %v, valid: %v", err, test.valid)
+			}
+		})
+	}
+
+	// the task sets are also checked, e.g., for a period of 0
+	content := `{"format": "taskset", "version": 1, "tasks": [{"task_id": 0, "jitter": 0, "bcet": 1, "wcet": 2,
+		"period": 0, "deadline": 10, "pe": 0}]}`
+	if _, err := ReadTaskSetJSON(writeFile(t, "taskset.json", content)); err == nil {
+		t.Error("a task with a period of 0 should be invalid")
+	}
+}
+
+func TestWriteSchemas(t *testing.T) {
+	dir := t.TempDir()
+	if err := WriteSchemas(dir); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"taskset.v1.json", "vertexset.v1.json", "jobset.v1.json"} {
+		written, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		embedded, _ := schemas.ReadFile("schemas/" + name)
+		if string(written) != string(embedded) {
+			t.Errorf("the written schema %s is not the schema of the reader", name)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// schema is the part of JSON Schema that the schemas of the JSON files use
+type schema struct {
+	Type                 string             `json:"type"`
+	Const                interface{}        `json:"const"`
+	Minimum              *float64           `json:"minimum"`
+	Required             []string           `json:"required"`
+	Properties           map[string]*schema `json:"properties"`
+	AdditionalProperties *bool              `json:"additionalProperties"`
+	Items                *schema            `json:"items"`
+	PrefixItems          []*schema          `json:"prefixItems"`
+	MinItems             *int               `json:"minItems"`
+	MaxItems             *int               `json:"maxItems"`
+}
+
+// validateJSON checks a JSON file against its schema in the schemas folder, e.g., "jobset.v1.json"
+func validateJSON(data []byte, schemaName string) error {
+	raw, err := schemas.ReadFile("schemas/" + schemaName)
+	if err != nil {
+		return err
+	}
+	var s schema
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return fmt.Errorf("invalid schema %s: %v", schemaName, err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	// the numbers are kept as they are written, so the integers can be told apart from the other numbers
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return err
+	}
+	return s.validate(value, "")
+}
+
+// validate checks a value of a JSON file, at the given location (e.g., "/jobs/3/task_id")
+func (s *schema) validate(value interface{}, at string) error {
+	if s.Const != nil && fmt.Sprint(s.Const) != fmt.Sprint(value) {
+		return fmt.Errorf("%s should be %v", location(at), s.Const)
+	}
+	switch s.Type {
+	case "object":
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s should be an object", location(at))
+		}
+		for _, key := range s.Required {
+			if _, ok := fields[key]; !ok {
+				return fmt.Errorf("%s misses %q", location(at), key)
+			}
+		}
+		keys := make([]string, 0, len(fields))
+		for key := range fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			property, ok := s.Properties[key]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					return fmt.Errorf("%s has the unknown key %q", location(at), key)
+				}
+				continue
+			}
+			if err := property.validate(fields[key], at+"/"+key); err != nil {
+				return err
+			}
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s should be an array", location(at))
+		}
+		if s.MinItems != nil && len(items) < *s.MinItems || s.MaxItems != nil && len(items) > *s.MaxItems {
+			return fmt.Errorf("%s has %d items", location(at), len(items))
+		}
+		for i, item := range items {
+			itemSchema := s.Items
+			if i < len(s.PrefixItems) {
+				itemSchema = s.PrefixItems[i]
+			}
+			if itemSchema == nil {
+				continue
+			}
+			if err := itemSchema.validate(item, fmt.Sprintf("%s/%d", at, i)); err != nil {
+				return err
+			}
+		}
+	case "integer":
+		number, ok := value.(json.Number)
+		if !ok {
+			return fmt.Errorf("%s should be an integer", location(at))
+		}
+		n, err := number.Int64()
+		if err != nil {
+			return fmt.Errorf("%s should be an integer", location(at))
+		}
+		if s.Minimum != nil && float64(n) < *s.Minimum {
+			return fmt.Errorf("%s should be at least %v", location(at), *s.Minimum)
+		}
+	}
+	return nil
+}
+
+// location names a location of a JSON file in the errors
+func location(at string) string {
+	if at == "" {
+		return "the file"
+	}
+	return at
+}
@@ -18,13 +18,20 @@ type Task struct {
 	Period   int
 	Deadline int
 	PE       int
+	Offset   int
+	// Criticality is the criticality level of the task, and WCETs its WCET at each level up to its own level
+	Criticality int
+	WCETs       []int
+	// Priority is an explicit fixed priority of the task, where a smaller value is a higher priority and 0 means none
+	Priority int
 }
 
 type TaskSet []*Task
 
 func (t *Task) String() string {
-	return "{ " + string(t.TaskID) + " " + string(t.Jitter) + " " + string(t.BCET) + " " + string(t.WCET) +
-		" " + string(t.Period) + " " + string(t.Deadline) + " " + string(t.PE) + " }"
+	return "{ " + strconv.Itoa(t.TaskID) + " " + strconv.Itoa(t.Jitter) + " " + strconv.Itoa(t.BCET) + " " +
+		strconv.Itoa(t.WCET) + " " + strconv.Itoa(t.Period) + " " + strconv.Itoa(t.Deadline) + " " +
+		strconv.Itoa(t.PE) + " " + strconv.Itoa(t.Offset) + " }"
 }
 
 // gcd calculates the greatest common divisor of two numbers
@@ -51,6 +58,16 @@ func abs(x int) int {
 	return x
 }
 
+// hasPriorities reports whether a task of the task set has an explicit priority
+func (ts TaskSet) hasPriorities() bool {
+	for _, t := range ts {
+		if t.Priority != 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // SortByPeriod function to sort tasks by period
 func (ts *TaskSet) SortByPeriod() {
 	// sort the tasks in ts by period
@@ -74,12 +91,46 @@ func (ts TaskSet) HyperPeriod() int {
 	return hyperperiod
 }
 
-// NumJobs function to calculate the number of jobs in the hyperperiod
-func (ts TaskSet) NumJobs(hyperperiod int) int {
-	// calculate the number of jobs in the hyperperiod
+// Utilization function to calculate the total utilization of a task set
+func (ts TaskSet) Utilization() float64 {
+	utilization := 0.0
+	for _, t := range ts {
+		utilization += float64(t.WCET) / float64(t.Period)
+	}
+	return utilization
+}
+
+// MaxOffset returns the largest release offset of the tasks
+func (ts TaskSet) MaxOffset() int {
+	maxOffset := 0
+	for _, t := range ts {
+		maxOffset = max(maxOffset, t.Offset)
+	}
+	return maxOffset
+}
+
+// FeasibilityInterval returns the length of the interval in which the jobs of the tasks are released.
+// For synchronous tasks it is the hyperperiod, and with offsets it is the largest offset plus two hyperperiods.
+func FeasibilityInterval(hyperperiod, maxOffset int) int {
+	if maxOffset == 0 {
+		return hyperperiod
+	}
+	return maxOffset + 2*hyperperiod
+}
+
+// NumReleases returns the number of jobs of a task with the given period and offset released in [0, interval)
+func NumReleases(period, offset, interval int) int {
+	if offset >= interval {
+		return 0
+	}
+	return (interval - offset + period - 1) / period
+}
+
+// NumJobs function to calculate the number of jobs released in an interval, e.g., the hyperperiod
+func (ts TaskSet) NumJobs(interval int) int {
 	numJobs := 0
 	for _, t := range ts {
-		numJobs += hyperperiod / t.Period
+		numJobs += NumReleases(t.Period, t.Offset, interval)
 	}
 	return numJobs
 }
@@ -163,6 +214,22 @@ func (ts TaskSet) WriteTaskSet(path string) error {
 	defer writer.Flush()
 
 	headers := []string{"TaskID", "Jitter", "BCET", "WCET", "Period", "Deadline", "PE"}
+	// the offsets are only added for task sets with offsets, so the other task sets keep the columns of the
+	// older versions
+	offsets := ts.MaxOffset() > 0
+	if offsets {
+		headers = append(headers, "Offset")
+	}
+	// the budgets of the criticality levels are only added for mixed-criticality task sets, like in the job sets
+	mixedCriticality := ts.IsMixedCriticality()
+	if mixedCriticality {
+		headers = append(headers, "Criticality", "WCETs")
+	}
+	// the explicit priorities are only added for the task sets that have them
+	priorities := ts.hasPriorities()
+	if priorities {
+		headers = append(headers, "Priority")
+	}
 	writer.Write(headers)
 
 	for i := range ts {
@@ -176,6 +243,15 @@ func (ts TaskSet) WriteTaskSet(path string) error {
 			strconv.Itoa(ts[i].Deadline),
 			strconv.Itoa(ts[i].PE),
 		}
+		if offsets {
+			row = append(row, strconv.Itoa(ts[i].Offset))
+		}
+		if mixedCriticality {
+			row = append(row, strconv.Itoa(ts[i].Criticality), intListString(ts[i].Budgets()))
+		}
+		if priorities {
+			row = append(row, strconv.Itoa(ts[i].Priority))
+		}
 		writer.Write(row)
 	}
 
@@ -195,6 +271,9 @@ func (ts TaskSet) WriteTaskSetYAML(path string) error {
 	// first, we need to add taskset as the root element
 	_, err = file.WriteString("taskset:\n")
 	// then, we add the tasks
+	offsets := ts.MaxOffset() > 0
+	mixedCriticality := ts.IsMixedCriticality()
+	priorities := ts.hasPriorities()
 	for i, t := range ts {
 		_, err = file.WriteString(fmt.Sprintf("  - TaskID: %d\n", i))
 		_, err = file.WriteString(fmt.Sprintf("    Jitter: %d\n", t.Jitter))
@@ -203,6 +282,16 @@ func (ts TaskSet) WriteTaskSetYAML(path string) error {
 		_, err = file.WriteString(fmt.Sprintf("    period: %d\n", t.Period))
 		_, err = file.WriteString(fmt.Sprintf("    deadline: %d\n", t.Deadline))
 		_, err = file.WriteString(fmt.Sprintf("    PE: %d\n", t.PE))
+		if offsets {
+			_, err = file.WriteString(fmt.Sprintf("    Offset: %d\n", t.Offset))
+		}
+		if mixedCriticality {
+			_, err = file.WriteString(fmt.Sprintf("    Criticality: %d\n", t.Criticality))
+			_, err = file.WriteString(fmt.Sprintf("    WCETs: %s\n", intListString(t.Budgets())))
+		}
+		if priorities {
+			_, err = file.WriteString(fmt.Sprintf("    Priority: %d\n", t.Priority))
+		}
 
 	}
 	return nil
@@ -221,9 +310,15 @@ func ReadTaskSet(path string) (TaskSet, error) {
 	reader := csv.NewReader(file)
 	reader.TrimLeadingSpace = true
 
-	// skip the header
-	if _, err := reader.Read(); err != nil {
-		panic(err)
+	// the columns are read by their names, since the offset, the criticality, and the priority columns are only
+	// written for the task sets that use them, and the files of older versions miss the newer columns
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the header of %s: %v", path, err)
+	}
+	columns := newCSVColumns(header)
+	if err := columns.require("TaskID", "Jitter", "BCET", "WCET", "Period", "Deadline", "PE"); err != nil {
+		return nil, fmt.Errorf("invalid task set %s: %v", path, err)
 	}
 
 	records, err := reader.ReadAll()
@@ -232,22 +327,24 @@ func ReadTaskSet(path string) (TaskSet, error) {
 	}
 
 	for _, record := range records {
-		tempID, _ := strconv.Atoi(record[0])
-		tempJitter, _ := strconv.Atoi(record[1])
-		tempBCET, _ := strconv.Atoi(record[2])
-		tempWCET, _ := strconv.Atoi(record[3])
-		tempPeriod, _ := strconv.Atoi(record[4])
-		tempDeadline, _ := strconv.Atoi(record[5])
-		tempPE, _ := strconv.Atoi(record[6])
+		values, err := columns.ints(record, "TaskID", "Jitter", "BCET", "WCET", "Period", "Deadline", "PE", "Offset",
+			"Criticality", "Priority")
+		if err != nil {
+			return nil, fmt.Errorf("invalid task in %s: %v", path, err)
+		}
 
 		tasks = append(tasks, &Task{
-			TaskID:   tempID,
-			Jitter:   tempJitter,
-			BCET:     tempBCET,
-			WCET:     tempWCET,
-			Period:   tempPeriod,
-			Deadline: tempDeadline,
-			PE:       tempPE,
+			TaskID:      values[0],
+			Jitter:      values[1],
+			BCET:        values[2],
+			WCET:        values[3],
+			Period:      values[4],
+			Deadline:    values[5],
+			PE:          values[6],
+			Offset:      values[7],
+			Criticality: values[8],
+			WCETs:       parseIntList(columns.field(record, "WCETs")),
+			Priority:    values[9],
 		})
 	}
 
@@ -273,22 +370,29 @@ func ReadTaskSetYAML(path string) (TaskSet, error) {
 
 	// then, we need to iterate over the task set
 	for _, t := range taskSet["taskset"] {
-		tempID := int(t["TaskID"].(int))
-		tempJitter := int(t["Jitter"].(int))
-		tempBCET := int(t["BCET"].(int))
-		tempWCET := int(t["WCET"].(int))
-		tempPeriod := int(t["period"].(int))
-		tempDeadline := int(t["deadline"].(int))
-		tempPE := int(t["PE"].(int))
+		values, err := yamlInts(t, "TaskID", "Jitter", "BCET", "WCET", "period", "deadline", "PE")
+		if err != nil {
+			return nil, fmt.Errorf("invalid task in %s: %v", path, err)
+		}
+		tempID, tempJitter, tempBCET, tempWCET := values[0], values[1], values[2], values[3]
+		tempPeriod, tempDeadline, tempPE := values[4], values[5], values[6]
+		tempOffset, _ := t["Offset"].(int)
+		tempCriticality, _ := t["Criticality"].(int)
+		tempWCETs := yamlIntList(t["WCETs"])
+		tempPriority, _ := t["Priority"].(int)
 
 		tasks = append(tasks, &Task{
-			TaskID:   tempID,
-			Jitter:   tempJitter,
-			BCET:     tempBCET,
-			WCET:     tempWCET,
-			Period:   tempPeriod,
-			Deadline: tempDeadline,
-			PE:       tempPE,
+			TaskID:      tempID,
+			Jitter:      tempJitter,
+			BCET:        tempBCET,
+			WCET:        tempWCET,
+			Period:      tempPeriod,
+			Deadline:    tempDeadline,
+			PE:          tempPE,
+			Offset:      tempOffset,
+			Criticality: tempCriticality,
+			WCETs:       tempWCETs,
+			Priority:    tempPriority,
 		})
 	}
 
@@ -2,6 +2,7 @@ package common
 
 import (
 	"encoding/csv"
+	"fmt"
 	"gopkg.in/yaml.v2"
 	"os"
 	"strconv"
@@ -17,6 +18,10 @@ type Vertex struct {
 	WCET         int
 	Period       int
 	Deadline     int
+	PE           int
+	Offset       int
+	Criticality  int
+	WCETs        []int
 	Predecessors []int
 	Successors   []int
 	Depth        int
@@ -41,7 +46,7 @@ func (vs *VertexSet) GenerateDotFile(name string, offset int) string {
 	// write the edges
 	for _, vertex := range *vs {
 		for _, successor := range vertex.Successors {
-			str += "\t" + strconv.Itoa(vertex.VertexID+offset) + " -> " + strconv.Itoa((*vs)[successor].VertexID+offset) + ";\n"
+			str += "\t" + strconv.Itoa(vertex.VertexID+offset) + " -> " + strconv.Itoa(successor+offset) + ";\n"
 		}
 	}
 
@@ -51,6 +56,110 @@ func (vs *VertexSet) GenerateDotFile(name string, offset int) string {
 	return str
 }
 
+// successorString writes the successors of a vertex as "[1,2,3]"
+func (v *Vertex) successorString() string {
+	return intListString(v.Successors)
+}
+
+// WriteVertexSet writes a vertex set to a CSV file
+func (vs VertexSet) WriteVertexSet(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	headers := []string{"Task ID", "Vertex ID", "Jitter", "BCET", "WCET", "Period", "Deadline", "PE", "Successors"}
+	// the offsets and the budgets of the criticality levels are only added for the DAGs that use them, like in the
+	// task sets
+	offsets := vs.maxOffset() > 0
+	if offsets {
+		headers = append(headers, "Offset")
+	}
+	mixedCriticality := vs.isMixedCriticality()
+	if mixedCriticality {
+		headers = append(headers, "Criticality", "WCETs")
+	}
+	writer.Write(headers)
+
+	for _, vertex := range vs {
+		row := []string{
+			strconv.Itoa(vertex.TaskID),
+			strconv.Itoa(vertex.VertexID),
+			strconv.Itoa(vertex.Jitter),
+			strconv.Itoa(vertex.BCET),
+			strconv.Itoa(vertex.WCET),
+			strconv.Itoa(vertex.Period),
+			strconv.Itoa(vertex.Deadline),
+			strconv.Itoa(vertex.PE),
+			vertex.successorString(),
+		}
+		if offsets {
+			row = append(row, strconv.Itoa(vertex.Offset))
+		}
+		if mixedCriticality {
+			row = append(row, strconv.Itoa(vertex.Criticality), intListString(vertex.Budgets()))
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteVertexSetYAML writes a vertex set to a YAML file
+func (vs VertexSet) WriteVertexSetYAML(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// we need to add vertexset as the root element
+	if _, err := file.WriteString("vertexset:\n"); err != nil {
+		return err
+	}
+	// then, we add the vertices
+	offsets := vs.maxOffset() > 0
+	mixedCriticality := vs.isMixedCriticality()
+	for _, vertex := range vs {
+		offset, criticality := "", ""
+		if offsets {
+			offset = fmt.Sprintf("    Offset: %d\n", vertex.Offset)
+		}
+		if mixedCriticality {
+			criticality = fmt.Sprintf("    Criticality: %d\n", vertex.Criticality) +
+				fmt.Sprintf("    WCETs: %s\n", intListString(vertex.Budgets()))
+		}
+		_, err = file.WriteString(fmt.Sprintf("  - TaskID: %d\n", vertex.TaskID) +
+			fmt.Sprintf("    VertexID: %d\n", vertex.VertexID) +
+			fmt.Sprintf("    Jitter: %d\n", vertex.Jitter) +
+			fmt.Sprintf("    BCET: %d\n", vertex.BCET) +
+			fmt.Sprintf("    WCET: %d\n", vertex.WCET) +
+			fmt.Sprintf("    Period: %d\n", vertex.Period) +
+			fmt.Sprintf("    Deadline: %d\n", vertex.Deadline) +
+			fmt.Sprintf("    PE: %d\n", vertex.PE) +
+			fmt.Sprintf("    Successors: %s\n", vertex.successorString()) +
+			offset + criticality)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// maxOffset returns the largest release offset of the vertices
+func (vs VertexSet) maxOffset() int {
+	maxOffset := 0
+	for _, vertex := range vs {
+		maxOffset = max(maxOffset, vertex.Offset)
+	}
+	return maxOffset
+}
+
 // Sort sorts the vertex set based on the vertex ID
 func (vs *VertexSet) Sort() {
 	// sort the vertex set
@@ -77,9 +186,16 @@ func ReadVertexSet(path string) (VertexSet, error) {
 	reader := csv.NewReader(file)
 	reader.TrimLeadingSpace = true
 
-	// skip the header
-	if _, err := reader.Read(); err != nil {
-		panic(err)
+	// the columns are read by their names, since the offset and the criticality columns are only written for the
+	// DAGs that use them
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the header of %s: %v", path, err)
+	}
+	columns := newCSVColumns(header)
+	if err := columns.require("Task ID", "Vertex ID", "Jitter", "BCET", "WCET", "Period", "Deadline", "PE",
+		"Successors"); err != nil {
+		return nil, fmt.Errorf("invalid vertex set %s: %v", path, err)
 	}
 
 	records, err := reader.ReadAll()
@@ -88,15 +204,13 @@ func ReadVertexSet(path string) (VertexSet, error) {
 	}
 
 	for _, record := range records {
-		tempTaskID, _ := strconv.Atoi(record[0])
-		tempVertexID, _ := strconv.Atoi(record[1])
-		tempJitter, _ := strconv.Atoi(record[2])
-		tempBCET, _ := strconv.Atoi(record[3])
-		tempWCET, _ := strconv.Atoi(record[4])
-		tempPeriod, _ := strconv.Atoi(record[5])
-		tempDeadline, _ := strconv.Atoi(record[6])
+		values, err := columns.ints(record, "Task ID", "Vertex ID", "Jitter", "BCET", "WCET", "Period", "Deadline",
+			"PE", "Offset", "Criticality")
+		if err != nil {
+			return nil, fmt.Errorf("invalid vertex in %s: %v", path, err)
+		}
 		// for successors, we have to first remove the brackets
-		tempSc := record[7][1 : len(record[7])-1]
+		tempSc := strings.Trim(columns.field(record, "Successors"), "[]")
 		tempSuccessors := []string{}
 		if len(strings.TrimSpace(tempSc)) != 0 {
 			tempSuccessors = strings.Split(tempSc, ",")
@@ -104,19 +218,26 @@ func ReadVertexSet(path string) (VertexSet, error) {
 
 		var successors []int
 		for _, successor := range tempSuccessors {
-			temp, _ := strconv.Atoi(successor)
+			temp, err := strconv.Atoi(strings.TrimSpace(successor))
+			if err != nil {
+				return nil, fmt.Errorf("invalid vertex in %s: invalid successor %q", path, successor)
+			}
 			successors = append(successors, temp)
 		}
 
 		vertices = append(vertices, &Vertex{
-			TaskID:     tempTaskID,
-			VertexID:   tempVertexID,
-			Jitter:     tempJitter,
-			BCET:       tempBCET,
-			WCET:       tempWCET,
-			Period:     tempPeriod,
-			Deadline:   tempDeadline,
-			Successors: successors,
+			TaskID:      values[0],
+			VertexID:    values[1],
+			Jitter:      values[2],
+			BCET:        values[3],
+			WCET:        values[4],
+			Period:      values[5],
+			Deadline:    values[6],
+			PE:          values[7],
+			Offset:      values[8],
+			Criticality: values[9],
+			WCETs:       parseIntList(columns.field(record, "WCETs")),
+			Successors:  successors,
 		})
 	}
 
@@ -142,34 +263,47 @@ func ReadVertexSetYAML(path string) (VertexSet, error) {
 
 	for _, vertex := range vertexSet["vertexset"] {
 		// then, we need to iterate over the vertex set
-		tempTaskID := int(vertex["TaskID"].(int))
-		tempVertexID := int(vertex["VertexID"].(int))
-		tempJitter := int(vertex["Jitter"].(int))
-		tempBCET := int(vertex["BCET"].(int))
-		tempWCET := int(vertex["WCET"].(int))
-		tempPeriod := int(vertex["Period"].(int))
-		tempDeadline := int(vertex["Deadline"].(int))
-
-		var successors []int
-		for _, successor := range vertex["Successors"].([]interface{}) {
-			successors = append(successors, int(successor.(int)))
+		values, err := yamlInts(vertex, "TaskID", "VertexID", "Jitter", "BCET", "WCET", "Period", "Deadline")
+		if err != nil {
+			return nil, fmt.Errorf("invalid vertex in %s: %v", path, err)
 		}
+		tempTaskID, tempVertexID, tempJitter, tempBCET := values[0], values[1], values[2], values[3]
+		tempWCET, tempPeriod, tempDeadline := values[4], values[5], values[6]
+		tempPE, _ := vertex["PE"].(int)
+		tempOffset, _ := vertex["Offset"].(int)
+		tempCriticality, _ := vertex["Criticality"].(int)
+		tempWCETs := yamlIntList(vertex["WCETs"])
+
+		successors := yamlIntList(vertex["Successors"])
 
 		vertices = append(vertices, &Vertex{
-			TaskID:     tempTaskID,
-			VertexID:   tempVertexID,
-			Jitter:     tempJitter,
-			BCET:       tempBCET,
-			WCET:       tempWCET,
-			Period:     tempPeriod,
-			Deadline:   tempDeadline,
-			Successors: successors,
+			TaskID:      tempTaskID,
+			VertexID:    tempVertexID,
+			Jitter:      tempJitter,
+			BCET:        tempBCET,
+			WCET:        tempWCET,
+			Period:      tempPeriod,
+			Deadline:    tempDeadline,
+			PE:          tempPE,
+			Offset:      tempOffset,
+			Criticality: tempCriticality,
+			WCETs:       tempWCETs,
+			Successors:  successors,
 		})
 
 	}
 	return vertices, nil
 }
 
+// MaxOffset returns the largest release offset of the vertices
+func (vs VertexSet) MaxOffset() int {
+	maxOffset := 0
+	for _, v := range vs {
+		maxOffset = max(maxOffset, v.Offset)
+	}
+	return maxOffset
+}
+
 // HyperPeriod function to calculate the hyperperiod of the vertex set
 func (vs VertexSet) HyperPeriod() int {
 	// calculate the hyperperiod
@@ -0,0 +1,38 @@
+package lib
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"task-generator/lib/common"
+)
+
+// checkCriticality checks the fraction of HI tasks and the ratio of the HI to the LO WCET
+func checkCriticality(hiFraction, wcetFactor float64) error {
+	if hiFraction < 0 || hiFraction > 1 {
+		return fmt.Errorf("the fraction of HI tasks should be in [0, 1]")
+	}
+	if hiFraction > 0 && wcetFactor < 1 {
+		return fmt.Errorf("the ratio of the HI to the LO WCET should be at least 1")
+	}
+	return nil
+}
+
+// generateCriticality makes a fraction of the tasks HI tasks, chosen at random. The WCET of a task is its LO budget,
+// and a HI task gets a HI budget of wcetFactor times its LO budget, up to its deadline.
+func generateCriticality(rng *rand.Rand, tasks common.TaskSet, hiFraction, wcetFactor float64) {
+	if hiFraction == 0 {
+		return
+	}
+	numHI := int(math.Round(hiFraction * float64(len(tasks))))
+	for i, index := range rng.Perm(len(tasks)) {
+		task := tasks[index]
+		if i < numHI {
+			task.Criticality = common.HI
+			task.WCETs = []int{task.WCET, min(int(wcetFactor*float64(task.WCET)), task.Deadline)}
+		} else {
+			task.Criticality = common.LO
+			task.WCETs = []int{task.WCET}
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package lib
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"task-generator/lib/common"
+)
+
+// checkDeadlineModel checks the deadline model and its factor
+func checkDeadlineModel(model string, factor float64) error {
+	switch model {
+	case "", "implicit":
+	case "constrained":
+		// without a factor, the deadline is between the execution time plus the jitter and the period
+		if factor < 0 || factor > 1 {
+			return fmt.Errorf("the deadline factor of constrained deadlines should be in [0, 1]")
+		}
+	case "arbitrary":
+		if factor < 1 {
+			return fmt.Errorf("the deadline factor of arbitrary deadlines should be at least 1")
+		}
+	default:
+		return fmt.Errorf("unknown deadline model: %s", model)
+	}
+	return nil
+}
+
+// generateDeadlines sets the deadline of each task with the deadline model:
+// "implicit" D = T, "constrained" D in [C + J, T] or in [max(factor * T, C + J), T], and "arbitrary" D in
+// [C + J, factor * T]
+func generateDeadlines(rng *rand.Rand, tasks common.TaskSet, model string, factor float64) {
+	for _, task := range tasks {
+		low, high := task.Period, task.Period
+		switch model {
+		case "constrained":
+			// the deadline is never shorter than the execution time plus the jitter
+			low = task.WCET + task.Jitter
+			if factor > 0 {
+				low = max(low, int(math.Ceil(factor*float64(task.Period))))
+			}
+		case "arbitrary":
+			low = task.WCET + task.Jitter
+			high = int(factor * float64(task.Period))
+		}
+		if high > low {
+			// both ends are included
+			task.Deadline = low + rng.Intn(high-low+1)
+		} else {
+			task.Deadline = high
+		}
+	}
+}
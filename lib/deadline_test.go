@@ -0,0 +1,42 @@
+package lib
+
+import (
+	"math/rand"
+	"task-generator/lib/common"
+	"testing"
+)
+
+func TestGenerateDeadlines(t *testing.T) {
+	tests := []struct {
+		name      string
+		model     string
+		factor    float64
+		low, high int
+	}{
+		{"implicit", "implicit", 0, 100, 100},
+		{"constrained", "constrained", 0, 45, 100},
+		{"constrained with a factor", "constrained", 0.8, 80, 100},
+		// the factor gives a shorter deadline than the execution time plus the jitter
+		{"constrained with a small factor", "constrained", 0.2, 45, 100},
+		{"arbitrary", "arbitrary", 2, 45, 200},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(1))
+			seen := make(map[int]bool)
+			for sample := 0; sample < 2000; sample++ {
+				tasks := common.TaskSet{{WCET: 40, Jitter: 5, Period: 100}}
+				generateDeadlines(rng, tasks, test.model, test.factor)
+				deadline := tasks[0].Deadline
+				if deadline < test.low || deadline > test.high {
+					t.Fatalf("deadline %d is not within [%d, %d]", deadline, test.low, test.high)
+				}
+				seen[deadline] = true
+			}
+			// both ends are drawn
+			if !seen[test.low] || !seen[test.high] {
+				t.Errorf("the deadlines %d and %d are not drawn", test.low, test.high)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package lib
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+//	The "drs" distribution samples the utilizations uniformly from the simplex of the utilizations that sum to the
+//	total utilization, constrained by an upper and a lower bound of each task, as the Dirichlet-Rescale algorithm of
+//	D. Griffin, I. Bate, and R. I. Davis, "Generating Utilization Vectors for the Systematic Evaluation of
+//	Schedulability Tests", (RTSS 2020), 2020.
+//	Instead of the rescaling steps of the paper, the sample is drawn from one of the two simplices that enclose the
+//	constrained region and drawn again while it is outside the region, which gives the same uniform distribution.
+
+// drsMaxAttempts is the number of samples drawn before the bounds are considered too tight
+const drsMaxAttempts = 1000000
+
+// drsEpsilon is the tolerance of the checks of the bounds
+const drsEpsilon = 1e-9
+
+// drs generates n utilizations that sum to u, with lower[i] <= x[i] <= upper[i], uniformly over all such utilizations
+func drs(rng *rand.Rand, u float64, lower, upper []float64) ([]float64, error) {
+	n := len(lower)
+	if n == 1 {
+		return []float64{u}, nil
+	}
+	sumLower, sumUpper := 0.0, 0.0
+	for i := 0; i < n; i++ {
+		sumLower += lower[i]
+		sumUpper += upper[i]
+	}
+	if u < sumLower-drsEpsilon || u > sumUpper+drsEpsilon {
+		return nil, fmt.Errorf("the utilization %.2f is not within the bounds [%.2f, %.2f]", u, sumLower, sumUpper)
+	}
+
+	// first we tighten the bounds, each task has to leave enough utilization for the others and take what they
+	// cannot take
+	low := make([]float64, n)
+	high := make([]float64, n)
+	for i := 0; i < n; i++ {
+		low[i] = max(lower[i], u-(sumUpper-upper[i]))
+		high[i] = min(upper[i], u-(sumLower-lower[i]))
+	}
+
+	// now we shift the lower bounds to 0, so we sample y = x - low with sum(y) = s and y[i] <= c[i]
+	s := u
+	sumC := 0.0
+	c := make([]float64, n)
+	for i := 0; i < n; i++ {
+		s -= low[i]
+		c[i] = high[i] - low[i]
+		sumC += c[i]
+	}
+	if s <= drsEpsilon {
+		return low, nil
+	}
+	if sumC-s <= drsEpsilon {
+		return high, nil
+	}
+
+	// the region is in the simplex sum(y) = s, y >= 0, and in the simplex sum(c - y) = sumC - s, c - y >= 0.
+	// we sample from the smaller one, which has more of its volume in the region
+	inverted := sumC-s < s
+	side := s
+	if inverted {
+		side = sumC - s
+	}
+	y := make([]float64, n)
+	for attempt := 0; attempt < drsMaxAttempts; attempt++ {
+		// a uniform point of the simplex is a flat Dirichlet sample
+		total := 0.0
+		for i := range y {
+			y[i] = rng.ExpFloat64()
+			total += y[i]
+		}
+		inside := true
+		for i := range y {
+			y[i] *= side / total
+			if y[i] > c[i] {
+				inside = false
+				break
+			}
+		}
+		if !inside {
+			continue
+		}
+
+		x := make([]float64, n)
+		for i := range x {
+			if inverted {
+				x[i] = high[i] - y[i]
+			} else {
+				x[i] = low[i] + y[i]
+			}
+		}
+		return x, nil
+	}
+	return nil, fmt.Errorf("no utilizations within the bounds after %d samples, the bounds are too tight",
+		drsMaxAttempts)
+}
+
+// drsGenerator is the "drs" utilization distribution
+type drsGenerator struct{}
+
+// bounds returns the lower and the upper bound of each task. A shorter list is extended with its last value, and
+// without a list the common bounds are taken, by default [0, 1].
+func (drsGenerator) bounds(p UtilizationParams) ([]float64, []float64) {
+	defaultLower, defaultUpper := 0.0, 1.0
+	if len(p.Bounds) == 2 {
+		defaultLower, defaultUpper = p.Bounds[0], p.Bounds[1]
+	}
+	expand := func(bounds []float64, value float64) []float64 {
+		expanded := make([]float64, p.NumTasks)
+		for i := range expanded {
+			if i < len(bounds) {
+				value = bounds[i]
+			}
+			expanded[i] = value
+		}
+		return expanded
+	}
+	return expand(p.LowerBounds, defaultLower), expand(p.UpperBounds, defaultUpper)
+}
+
+// Check checks that the utilization can be reached within the bounds
+func (d drsGenerator) Check(p UtilizationParams) error {
+	if len(p.Bounds) != 0 && len(p.Bounds) != 2 {
+		return fmt.Errorf("the utilization bounds should be given as [min, max]")
+	}
+	if len(p.LowerBounds) > p.NumTasks || len(p.UpperBounds) > p.NumTasks {
+		return fmt.Errorf("more utilization bounds than the %d tasks", p.NumTasks)
+	}
+	lower, upper := d.bounds(p)
+	sumLower, sumUpper := 0.0, 0.0
+	for i := range lower {
+		if lower[i] < 0 || lower[i] > upper[i] {
+			return fmt.Errorf("invalid utilization bounds of task %d: [%.2f, %.2f]", i, lower[i], upper[i])
+		}
+		sumLower += lower[i]
+		sumUpper += upper[i]
+	}
+	if p.Utilization < sumLower-drsEpsilon || p.Utilization > sumUpper+drsEpsilon {
+		return fmt.Errorf("the utilization %.2f is not between the sum of the lower bounds %.2f and the sum of "+
+			"the upper bounds %.2f", p.Utilization, sumLower, sumUpper)
+	}
+	return nil
+}
+
+// Utilizations generates the utilizations within the bounds of each task
+func (d drsGenerator) Utilizations(rng *rand.Rand, p UtilizationParams) ([]float64, error) {
+	lower, upper := d.bounds(p)
+	return drs(rng, p.Utilization, lower, upper)
+}
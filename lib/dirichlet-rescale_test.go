@@ -0,0 +1,151 @@
+package lib
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// repeat returns a list of n times the value
+func repeat(value float64, n int) []float64 {
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = value
+	}
+	return values
+}
+
+func TestDRSBounds(t *testing.T) {
+	tests := []struct {
+		name         string
+		utilization  float64
+		lower, upper []float64
+	}{
+		{"without bounds", 2.5, repeat(0, 5), repeat(1, 5)},
+		{"common bounds", 1.2, repeat(0.1, 8), repeat(0.3, 8)},
+		{"lower bounds", 3, []float64{0.5, 0.4, 0.3, 0.2, 0.1, 0}, repeat(1, 6)},
+		{"upper bounds", 0.9, repeat(0, 4), []float64{0.1, 0.2, 0.3, 0.4}},
+		{"one heavy task", 1.2, repeat(0, 6), append([]float64{0.9}, repeat(0.2, 5)...)},
+		{"almost the sum of the upper bounds", 3.95, repeat(0, 10), []float64{1, 1, 1, 0.5, 0.1, 0.1, 0.1, 0.1, 0.05,
+			0.05}},
+		{"sum of the lower bounds", 0.6, repeat(0.1, 6), repeat(0.5, 6)},
+		{"sum of the upper bounds", 3, repeat(0, 6), repeat(0.5, 6)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(1))
+			for sample := 0; sample < 1000; sample++ {
+				util, err := drs(rng, test.utilization, test.lower, test.upper)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(util) != len(test.lower) {
+					t.Fatalf("%d utilizations for %d tasks", len(util), len(test.lower))
+				}
+				sum := 0.0
+				for i, u := range util {
+					if u < test.lower[i]-drsEpsilon || u > test.upper[i]+drsEpsilon {
+						t.Fatalf("utilization %f of task %d is not within [%f, %f]", u, i, test.lower[i],
+							test.upper[i])
+					}
+					sum += u
+				}
+				if math.Abs(sum-test.utilization) > 1e-6 {
+					t.Fatalf("utilizations sum to %f instead of %f", sum, test.utilization)
+				}
+			}
+		})
+	}
+}
+
+func TestDRSSymmetricMean(t *testing.T) {
+	// with the same bounds for all the tasks, each task gets the same share on average
+	rng := rand.New(rand.NewSource(1))
+	n, samples, utilization := 5, 20000, 1.5
+	mean := make([]float64, n)
+	for sample := 0; sample < samples; sample++ {
+		util, err := drs(rng, utilization, repeat(0.1, n), repeat(0.5, n))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, u := range util {
+			mean[i] += u / float64(samples)
+		}
+	}
+	for i, m := range mean {
+		if math.Abs(m-utilization/float64(n)) > 0.01 {
+			t.Errorf("mean utilization of task %d is %f instead of %f", i, m, utilization/float64(n))
+		}
+	}
+}
+
+func TestDRSAsymmetricDistribution(t *testing.T) {
+	// with u = 1 and the upper bounds [0.5, 1, 1], the utilizations are uniform over the triangle x0 + x1 <= 1 with
+	// x0 <= 0.5, so the density of x0 is proportional to 1 - x0 and P(x0 < a) = (a - a^2 / 2) / 0.375
+	cdf := func(a float64) float64 { return (a - a*a/2) / 0.375 }
+	tests := []struct {
+		name         string
+		utilization  float64
+		lower, upper []float64
+	}{
+		{"upper bounds", 1, []float64{0, 0, 0}, []float64{0.5, 1, 1}},
+		// the same triangle, shifted by the lower bounds
+		{"lower bounds", 1.3, []float64{0.1, 0.2, 0}, []float64{0.6, 1.2, 1}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(1))
+			samples := 20000
+			points := []float64{0.05, 0.1, 0.25, 0.4, 0.45}
+			below := make([]int, len(points))
+			for sample := 0; sample < samples; sample++ {
+				util, err := drs(rng, test.utilization, test.lower, test.upper)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for k, a := range points {
+					if util[0]-test.lower[0] < a {
+						below[k]++
+					}
+				}
+			}
+			for k, a := range points {
+				if p := float64(below[k]) / float64(samples); math.Abs(p-cdf(a)) > 0.02 {
+					t.Errorf("P(x0 < %.2f) = %.3f instead of %.3f", a, p, cdf(a))
+				}
+			}
+		})
+	}
+}
+
+func TestDRSTooTight(t *testing.T) {
+	// one heavy task among many light ones, where almost none of the simplex is within the bounds of the light tasks
+	rng := rand.New(rand.NewSource(1))
+	if _, err := drs(rng, 0.75, repeat(0, 31), append([]float64{0.9}, repeat(0.02, 30)...)); err == nil {
+		t.Error("the bounds should be too tight for the rejection")
+	}
+}
+
+func TestDRSGeneratorCheck(t *testing.T) {
+	tests := []struct {
+		name   string
+		params UtilizationParams
+		valid  bool
+	}{
+		{"default bounds", UtilizationParams{NumTasks: 4, Utilization: 2}, true},
+		{"too much utilization", UtilizationParams{NumTasks: 4, Utilization: 2, Bounds: []float64{0, 0.4}}, false},
+		{"too little utilization", UtilizationParams{NumTasks: 4, Utilization: 0.2, LowerBounds: []float64{0.1}},
+			false},
+		{"lower above upper", UtilizationParams{NumTasks: 2, Utilization: 0.5, LowerBounds: []float64{0.3},
+			UpperBounds: []float64{0.2, 0.4}}, false},
+		{"too many bounds", UtilizationParams{NumTasks: 2, Utilization: 0.5, UpperBounds: []float64{1, 1, 1}}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := drsGenerator{}.Check(test.params)
+			if (err == nil) != test.valid {
+				t.Errorf("Check() = %v, valid: %v", err, test.valid)
+			}
+		})
+	}
+}
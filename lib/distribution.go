@@ -0,0 +1,228 @@
+package lib
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+// UtilizationParams are the parameters of a utilization distribution
+type UtilizationParams struct {
+	NumTasks    int
+	Utilization float64
+	// Bounds are the [min, max] utilization of each task, only for the distributions that support them
+	Bounds []float64
+	// LowerBounds and UpperBounds are the bounds of the utilization of each task in turn, only for the distributions
+	// that support them. A shorter list is extended with its last value.
+	LowerBounds []float64
+	UpperBounds []float64
+}
+
+// PeriodParams are the parameters of a period distribution
+type PeriodParams struct {
+	NumTasks int
+	// Range is the [min, max] range of the periods
+	Range []int
+	// Periods are the predefined periods of the discrete distributions, sorted from large to small
+	Periods []int
+	// Base, Multipliers, and Chains are the first period, the multipliers between two successive periods, and the
+	// number of chains of the harmonic distributions (0 or nil for their defaults)
+	Base        int
+	Multipliers []int
+	Chains      int
+	// Factors is the factor matrix of the "divisors" distribution, whose periods take one factor of each row
+	// (nil for its default)
+	Factors [][]int
+}
+
+// UtilizationGenerator generates the utilization of each task of a task set
+type UtilizationGenerator interface {
+	// Check checks the parameters once, before any task set is generated
+	Check(p UtilizationParams) error
+	// Utilizations generates the utilizations of the tasks with the given random generator
+	Utilizations(rng *rand.Rand, p UtilizationParams) ([]float64, error)
+}
+
+// PeriodGenerator generates the period of each task of a task set
+type PeriodGenerator interface {
+	// Check checks the parameters once, before any task set is generated
+	Check(p PeriodParams) error
+	// Periods generates the periods of the tasks with the given random generator
+	Periods(rng *rand.Rand, p PeriodParams) ([]int, error)
+}
+
+// JointGenerator generates the utilizations and the periods of the tasks together, e.g., the automotive benchmark.
+// It can be used as both the utilization and the period distribution, or as the period distribution only, in which
+// case its utilizations are dropped. It decides the number of tasks by itself.
+type JointGenerator interface {
+	// Check checks the parameters once, before any task set is generated
+	Check(p UtilizationParams) error
+	// Tasks generates the utilizations and the periods of the tasks with the given random generator
+	Tasks(rng *rand.Rand, p UtilizationParams) ([]float64, []int, error)
+}
+
+// UtilizationFunc is a UtilizationGenerator without any parameter check
+type UtilizationFunc func(rng *rand.Rand, p UtilizationParams) ([]float64, error)
+
+// Check accepts all parameters
+func (f UtilizationFunc) Check(p UtilizationParams) error {
+	return nil
+}
+
+// Utilizations calls f
+func (f UtilizationFunc) Utilizations(rng *rand.Rand, p UtilizationParams) ([]float64, error) {
+	return f(rng, p)
+}
+
+// PeriodFunc is a PeriodGenerator without any parameter check
+type PeriodFunc func(rng *rand.Rand, p PeriodParams) ([]int, error)
+
+// Check accepts all parameters
+func (f PeriodFunc) Check(p PeriodParams) error {
+	return nil
+}
+
+// Periods calls f
+func (f PeriodFunc) Periods(rng *rand.Rand, p PeriodParams) ([]int, error) {
+	return f(rng, p)
+}
+
+// the registry of the distributions, with the built-in ones
+var (
+	registryLock          sync.RWMutex
+	utilizationGenerators = map[string]UtilizationGenerator{
+		"uunifast":       uunifastGenerator{},
+		"rand-fixed-sum": randFixedSumGenerator{},
+		"drs":            drsGenerator{},
+	}
+	periodGenerators = map[string]PeriodGenerator{
+		"uniform":              uniformPeriodGenerator{},
+		"uniform-discrete":     uniformPeriodGenerator{discrete: true},
+		"log-uniform":          logUniformPeriodGenerator{},
+		"log-uniform-discrete": logUniformPeriodGenerator{discrete: true},
+		"harmonic":             harmonicPeriodGenerator{},
+		"k-harmonic-chains":    harmonicPeriodGenerator{chains: true},
+		"divisors":             divisorPeriodGenerator{},
+	}
+	jointGenerators = map[string]JointGenerator{
+		"automotive": automotiveGenerator{},
+	}
+)
+
+// checkNoTaskBounds checks that no per-task utilization bounds are given to a distribution that does not support them
+func checkNoTaskBounds(p UtilizationParams) error {
+	if len(p.LowerBounds) > 0 || len(p.UpperBounds) > 0 {
+		return fmt.Errorf("the utilization bounds of each task are only valid for drs utilization distribution")
+	}
+	return nil
+}
+
+// registered reports whether a distribution with the given name exists
+func registered(name string) bool {
+	_, util := utilizationGenerators[name]
+	_, period := periodGenerators[name]
+	_, joint := jointGenerators[name]
+	return util || period || joint
+}
+
+// RegisterUtilizationGenerator registers a utilization distribution, so it can be used by its name,
+// e.g., in WithUtilizationDistribution or in the configuration file
+func RegisterUtilizationGenerator(name string, generator UtilizationGenerator) error {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	if generator == nil {
+		return fmt.Errorf("the utilization distribution %s is nil", name)
+	}
+	if registered(name) {
+		return fmt.Errorf("a distribution named %s is already registered", name)
+	}
+	utilizationGenerators[name] = generator
+	return nil
+}
+
+// RegisterPeriodGenerator registers a period distribution, so it can be used by its name,
+// e.g., in WithPeriodDistribution or in the configuration file
+func RegisterPeriodGenerator(name string, generator PeriodGenerator) error {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	if generator == nil {
+		return fmt.Errorf("the period distribution %s is nil", name)
+	}
+	if registered(name) {
+		return fmt.Errorf("a distribution named %s is already registered", name)
+	}
+	periodGenerators[name] = generator
+	return nil
+}
+
+// RegisterJointGenerator registers a distribution of both utilizations and periods, so it can be used by its name
+// as a utilization and as a period distribution
+func RegisterJointGenerator(name string, generator JointGenerator) error {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	if generator == nil {
+		return fmt.Errorf("the distribution %s is nil", name)
+	}
+	if registered(name) {
+		return fmt.Errorf("a distribution named %s is already registered", name)
+	}
+	jointGenerators[name] = generator
+	return nil
+}
+
+// UtilizationDistributions returns the names of all the utilization distributions, including the joint ones
+func UtilizationDistributions() []string {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	var names []string
+	for name := range utilizationGenerators {
+		names = append(names, name)
+	}
+	for name := range jointGenerators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PeriodDistributions returns the names of all the period distributions, including the joint ones
+func PeriodDistributions() []string {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	var names []string
+	for name := range periodGenerators {
+		names = append(names, name)
+	}
+	for name := range jointGenerators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookupUtilization finds a utilization distribution; only one of the results is set
+func lookupUtilization(name string) (UtilizationGenerator, JointGenerator, error) {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	if generator, ok := utilizationGenerators[name]; ok {
+		return generator, nil, nil
+	}
+	if generator, ok := jointGenerators[name]; ok {
+		return nil, generator, nil
+	}
+	return nil, nil, fmt.Errorf("unknown utilization distribution: %s", name)
+}
+
+// lookupPeriod finds a period distribution; only one of the results is set
+func lookupPeriod(name string) (PeriodGenerator, JointGenerator, error) {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	if generator, ok := periodGenerators[name]; ok {
+		return generator, nil, nil
+	}
+	if generator, ok := jointGenerators[name]; ok {
+		return nil, generator, nil
+	}
+	return nil, nil, fmt.Errorf("unknown period distribution: %s", name)
+}
@@ -0,0 +1,84 @@
+package lib
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+// defaultPeriodFactors is the factor matrix of the "divisors" period distribution without any given, whose
+// hyperperiod is 16 * 27 * 25 * 7 = 75600
+var defaultPeriodFactors = [][]int{
+	{1, 2, 4, 8, 16},
+	{1, 3, 9, 27},
+	{1, 5, 25},
+	{1, 7},
+}
+
+// divisorPeriodGenerator is the "divisors" period distribution of Goossens and Macq. Each period is the product of
+// one factor of each row of a factor matrix, e.g., a row of the powers of a prime, so the periods divide the product
+// of the least common multiples of the rows and the hyperperiod is bounded by construction, without rejecting any
+// task set. The periods are drawn uniformly from the products in the period range.
+type divisorPeriodGenerator struct{}
+
+// Check checks the period range and the factor matrix, which should give at least one period in the range
+func (d divisorPeriodGenerator) Check(p PeriodParams) error {
+	if err := checkPeriodRange(p, false); err != nil {
+		return err
+	}
+	for _, row := range p.Factors {
+		if len(row) == 0 {
+			return fmt.Errorf("the rows of the period factors should not be empty")
+		}
+		for _, f := range row {
+			if f < 1 {
+				return fmt.Errorf("the period factors should be positive")
+			}
+		}
+	}
+	if len(divisorPeriods(p)) == 0 {
+		return fmt.Errorf("no product of the period factors is in the period range [%d, %d]", p.Range[0],
+			p.Range[1])
+	}
+	return nil
+}
+
+// Periods draws the periods from the products of the factors in the period range
+func (d divisorPeriodGenerator) Periods(rng *rand.Rand, p PeriodParams) ([]int, error) {
+	candidates := divisorPeriods(p)
+	periods := make([]int, p.NumTasks)
+	for i := range periods {
+		periods[i] = candidates[rng.Intn(len(candidates))]
+	}
+	return periods, nil
+}
+
+// divisorPeriods returns the distinct products of one factor of each row of the factor matrix in the period range,
+// from small to large
+func divisorPeriods(p PeriodParams) []int {
+	factors := p.Factors
+	if len(factors) == 0 {
+		factors = defaultPeriodFactors
+	}
+	products := map[int]bool{1: true}
+	for _, row := range factors {
+		next := make(map[int]bool, len(products)*len(row))
+		for product := range products {
+			for _, f := range row {
+				// the products above the range only grow
+				if product*f <= p.Range[1] {
+					next[product*f] = true
+				}
+			}
+		}
+		products = next
+	}
+	var periods []int
+	for product := range products {
+		if product >= p.Range[0] {
+			periods = append(periods, product)
+		}
+	}
+	sort.Ints(periods)
+	return periods
+}
@@ -0,0 +1,138 @@
+package lib
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"task-generator/lib/analysis"
+	"task-generator/lib/common"
+	"task-generator/lib/export"
+)
+
+// ExportFormats are the formats of the tools that the task sets can be exported to, next to the task sets:
+// "simso" is a SimSo configuration file, "rt-app" an rt-app workload with the DAGs of the task sets, "cheddar" a
+// Cheddar ADL model, "mast" a MAST model, and "amalthea" an Amalthea (APP4MC) model with the chains of the task sets
+var ExportFormats = []string{"simso", "rt-app", "cheddar", "mast", "amalthea"}
+
+// exportExtensions are the extensions of the files of the export formats
+var exportExtensions = map[string]string{
+	"simso":    ".simso.xml",
+	"rt-app":   ".rtapp.json",
+	"cheddar":  ".cheddar.xml",
+	"mast":     ".mast.txt",
+	"amalthea": ".amxmi",
+}
+
+// exportPath returns the path of the export of a task set to a format, e.g., "uniform_0.simso.xml" for
+// "uniform_0.csv"
+func exportPath(taskSetPath, format string) string {
+	return taskSetPath[:strings.LastIndex(taskSetPath, ".")] + exportExtensions[format]
+}
+
+// checkExports checks the export formats
+func checkExports(formats []string) error {
+	for _, format := range formats {
+		if !slices.Contains(ExportFormats, format) {
+			return fmt.Errorf("invalid export format: %s", format)
+		}
+	}
+	return nil
+}
+
+// platform returns the platform of the exports of a task set, with the number of cores of its manifest and the
+// priorities of the priority assignment of the generator
+func (g *Generator) platform(tasks common.TaskSet, taskSetPath string) (export.Platform, error) {
+	platform := export.Platform{Cores: g.numCores, Policy: "EDF"}
+	if manifest, err := readManifest(manifestPath(taskSetPath)); err == nil && manifest.Cores > 0 {
+		// the sets of a sweep can have different numbers of cores
+		platform.Cores = manifest.Cores
+	}
+	if policy, ok := fixedPriorityPolicies[g.priorityAssignment]; ok {
+		priorities, err := analysis.Priorities(tasks, policy)
+		if err != nil {
+			return export.Platform{}, err
+		}
+		platform.Policy, platform.Priorities = policy, priorities
+	}
+	return platform, nil
+}
+
+// exportTaskSet exports a task set file to the export formats of the generator
+func (g *Generator) exportTaskSet(taskSetPath string) error {
+	tasks, err := readTaskSet(taskSetPath, g.outputFormat)
+	if err != nil {
+		return fmt.Errorf("error reading task set: %w", err)
+	}
+	platform, err := g.platform(tasks, taskSetPath)
+	if err != nil {
+		return err
+	}
+	// the DAG of the task set, if there is one
+	var vertices common.VertexSet
+	if _, err := os.Stat(precPath(taskSetPath, g.outputFormat)); err == nil {
+		if vertices, err = readVertexSet(precPath(taskSetPath, g.outputFormat), g.outputFormat); err != nil {
+			return fmt.Errorf("error reading DAG: %w", err)
+		}
+	}
+	for _, format := range g.exports {
+		switch format {
+		case "simso":
+			err = export.WriteSimSo(exportPath(taskSetPath, format), tasks, platform, g.simsoScheduler)
+		case "rt-app":
+			err = export.WriteRTApp(exportPath(taskSetPath, format), tasks, vertices, platform)
+		case "cheddar":
+			err = export.WriteCheddar(exportPath(taskSetPath, format), tasks, platform)
+		case "mast":
+			err = export.WriteMAST(exportPath(taskSetPath, format), tasks, platform)
+		case "amalthea":
+			err = export.WriteAmalthea(exportPath(taskSetPath, format), tasks, vertices, platform)
+		}
+		if err != nil {
+			return fmt.Errorf("error exporting to %s: %w", format, err)
+		}
+	}
+	return nil
+}
+
+// WriteExports exports each task set in the root folder to the export formats of the generator (see WithExports),
+// next to the task set. The exports are written again, so they follow the DAGs of the task sets.
+func (g *Generator) WriteExports(root string) error {
+	taskSetPaths, err := findTaskSetPaths(root, g.outputFormat)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, path := range taskSetPaths {
+		if err := g.exportTaskSet(path); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ImportAmalthea reads the tasks of an Amalthea (APP4MC) model and writes them as a task set in the format of its
+// extension ("csv", "yaml", or "json"), e.g., "waters.csv". The chains of tasks of the event chains of the model are
+// written as the DAG of the task set, e.g., "waters.prec.csv".
+func ImportAmalthea(modelPath, taskSetPath string) error {
+	format := strings.TrimPrefix(filepath.Ext(taskSetPath), ".")
+	if format != "csv" && format != "yaml" && format != "json" {
+		return fmt.Errorf("invalid task set format: %s", format)
+	}
+	tasks, vertices, err := export.ReadAmalthea(modelPath)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(taskSetPath), 0755); err != nil {
+		return err
+	}
+	if err := writeTaskSet(tasks, taskSetPath, format); err != nil {
+		return err
+	}
+	if vertices != nil {
+		return writeVertexSet(vertices, precPath(taskSetPath, format), format)
+	}
+	return nil
+}
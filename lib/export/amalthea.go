@@ -0,0 +1,774 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"math"
+	"net/url"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"task-generator/lib/common"
+)
+
+// amaltheaTicksPerUnit is the number of ticks of a time unit of the task set, which is one microsecond on the cores
+// of 1 GHz of the exported models
+const amaltheaTicksPerUnit = 1000
+
+// xsiNamespace is the namespace of the types of the elements of an Amalthea model
+const xsiNamespace = "http://www.w3.org/2001/XMLSchema-instance"
+
+// amRef returns the reference to an element of an Amalthea model, e.g., "T0?type=Task"
+func amRef(name, kind string) string {
+	return url.PathEscape(name) + "?type=" + kind
+}
+
+// amaltheaTime writes a time of the task set as an Amalthea time element
+func amaltheaTime(element string, t int) string {
+	return fmt.Sprintf("<%s value=\"%d\" unit=\"us\"/>", element, t)
+}
+
+// isTaskChain reports whether the vertices are the tasks of a chain of tasks, with a vertex for each task (see
+// generateTaskChain), rather than the vertices of the DAGs in the tasks
+func isTaskChain(tasks common.TaskSet, vertices common.VertexSet) bool {
+	if len(vertices) != len(tasks) {
+		return false
+	}
+	ids := make(map[int]bool, len(tasks))
+	for _, t := range tasks {
+		ids[t.TaskID] = true
+	}
+	for _, vertex := range vertices {
+		if vertex.VertexID != vertex.TaskID || !ids[vertex.TaskID] {
+			return false
+		}
+	}
+	return true
+}
+
+// chainPaths splits the edges of a graph of tasks into its maximal paths without branches, e.g., a chain of tasks is a
+// single path. Each path is the list of its task IDs.
+func chainPaths(vertices common.VertexSet) [][]int {
+	successors := make(map[int][]int, len(vertices))
+	inDegree := make(map[int]int, len(vertices))
+	for _, vertex := range vertices {
+		successors[vertex.VertexID] = vertex.Successors
+		for _, s := range vertex.Successors {
+			inDegree[s]++
+		}
+	}
+	// a path goes on through the vertices with one edge in and one edge out
+	through := func(v int) bool {
+		return inDegree[v] == 1 && len(successors[v]) == 1
+	}
+	var paths [][]int
+	for _, vertex := range vertices {
+		if through(vertex.VertexID) {
+			continue
+		}
+		for _, next := range vertex.Successors {
+			path := []int{vertex.VertexID, next}
+			for through(next) {
+				next = successors[next][0]
+				path = append(path, next)
+			}
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// WriteAmalthea writes the task set as an Amalthea (APP4MC) model. Each task is activated by a periodic stimulus with
+// its offset and jitter, has a response-time requirement of its deadline, and is allocated to the scheduler of its
+// core (PE) with its fixed priority (a larger value is a higher priority), or to EDF schedulers. The execution of a
+// task is a runnable with its BCET and WCET as ticks, or with a DAG in the tasks, a runnable for each vertex called in
+// a topological order, with a runnable sequencing constraint for each edge. The BCET and the WCET of each task are
+// also custom properties of the task, since they are not the sums of the vertices of its DAG. A chain of tasks is a
+// list of event chains from the end of a task to the start of the next one, one for each path of the chain without
+// branches. The time unit of the task set is one microsecond.
+func WriteAmalthea(path string, tasks common.TaskSet, vertices common.VertexSet, platform Platform) error {
+	if platform.Cores < 1 {
+		return fmt.Errorf("unknown number of cores for Amalthea")
+	}
+	levels := make(map[int]bool)
+	for _, p := range platform.Priorities {
+		levels[p] = true
+	}
+	priorities := descendingPriorities(platform.Priorities, len(levels), 1)
+
+	// the runnables of each task, which are defined in the order of the vertices, so they are read back with the
+	// same vertex IDs
+	chain := isTaskChain(tasks, vertices)
+	dag := len(vertices) > 0 && !chain
+	runnables := make(map[int][]*common.Vertex, len(tasks))
+	var definitions []*common.Vertex
+	if dag {
+		order, err := topologicalOrder(vertices)
+		if err != nil {
+			return err
+		}
+		for _, v := range order {
+			runnables[vertices[v].TaskID] = append(runnables[vertices[v].TaskID], vertices[v])
+		}
+		definitions = append(definitions, vertices...)
+	}
+	for _, t := range tasks {
+		if t.PE < 0 || t.PE >= platform.Cores {
+			return fmt.Errorf("task %d is mapped to core %d of %d cores", t.TaskID, t.PE, platform.Cores)
+		}
+		if len(runnables[t.TaskID]) == 0 {
+			r := &common.Vertex{TaskID: t.TaskID, VertexID: t.TaskID, BCET: t.BCET, WCET: t.WCET}
+			runnables[t.TaskID] = []*common.Vertex{r}
+			definitions = append(definitions, r)
+		}
+	}
+	runnableName := func(vertex *common.Vertex) string {
+		if dag {
+			return fmt.Sprintf("R%d_%d", vertex.TaskID, vertex.VertexID)
+		}
+		return fmt.Sprintf("R%d", vertex.TaskID)
+	}
+
+	var m strings.Builder
+	m.WriteString(xml.Header)
+	m.WriteString("<am:Amalthea xmlns:am=\"http://app4mc.eclipse.org/amalthea/2.0.0\" " +
+		"xmlns:xmi=\"http://www.omg.org/XMI\" xmlns:xsi=\"" + xsiNamespace + "\" xmi:version=\"2.0\">\n")
+
+	m.WriteString("  <swModel>\n")
+	for _, t := range tasks {
+		fmt.Fprintf(&m, "    <tasks name=\"T%d\" stimuli=\"%s\" preemption=\"preemptive\" "+
+			"multipleTaskActivationLimit=\"1\">\n", t.TaskID, amRef(fmt.Sprintf("stimulus_T%d", t.TaskID),
+			"PeriodicStimulus"))
+		for _, property := range []struct {
+			key   string
+			value int
+		}{{"BCET", t.BCET}, {"WCET", t.WCET}} {
+			fmt.Fprintf(&m, "      <customProperties key=\"%s\">\n        <value xsi:type=\"am:TimeObject\" "+
+				"value=\"%d\" unit=\"us\"/>\n      </customProperties>\n", property.key, property.value)
+		}
+		m.WriteString("      <activityGraph>\n")
+		for _, r := range runnables[t.TaskID] {
+			fmt.Fprintf(&m, "        <items xsi:type=\"am:RunnableCall\" runnable=\"%s\"/>\n",
+				amRef(runnableName(r), "Runnable"))
+		}
+		m.WriteString("      </activityGraph>\n    </tasks>\n")
+	}
+	for _, r := range definitions {
+		fmt.Fprintf(&m, "    <runnables name=\"%s\" callback=\"false\" service=\"false\">\n"+
+			"      <activityGraph>\n        <items xsi:type=\"am:Ticks\">\n"+
+			"          <default xsi:type=\"am:DiscreteValueBoundaries\" lowerBound=\"%d\" upperBound=\"%d\"/>\n"+
+			"        </items>\n      </activityGraph>\n    </runnables>\n", runnableName(r),
+			r.BCET*amaltheaTicksPerUnit, r.WCET*amaltheaTicksPerUnit)
+	}
+	m.WriteString("  </swModel>\n")
+
+	m.WriteString("  <hwModel>\n    <definitions xsi:type=\"am:ProcessingUnitDefinition\" name=\"Core\" " +
+		"puType=\"CPU\"/>\n    <structures name=\"System\" structureType=\"System\">\n")
+	for c := 0; c < platform.Cores; c++ {
+		fmt.Fprintf(&m, "      <modules xsi:type=\"am:ProcessingUnit\" name=\"core%d\" frequencyDomain=\"%s\" "+
+			"definition=\"%s\"/>\n", c, amRef("clock", "FrequencyDomain"),
+			amRef("Core", "ProcessingUnitDefinition"))
+	}
+	m.WriteString("    </structures>\n    <domains xsi:type=\"am:FrequencyDomain\" name=\"clock\" " +
+		"clockGating=\"false\">\n      <defaultValue value=\"1.0\" unit=\"GHz\"/>\n    </domains>\n  </hwModel>\n")
+
+	algorithm := "am:EarliestDeadlineFirst"
+	if platform.fixedPriority() {
+		algorithm = "am:FixedPriorityPreemptive"
+	}
+	m.WriteString("  <osModel>\n    <operatingSystems name=\"OS\">\n")
+	for c := 0; c < platform.Cores; c++ {
+		fmt.Fprintf(&m, "      <taskSchedulers name=\"scheduler%d\">\n        <schedulingAlgorithm "+
+			"xsi:type=\"%s\"/>\n      </taskSchedulers>\n", c, algorithm)
+	}
+	m.WriteString("    </operatingSystems>\n  </osModel>\n")
+
+	m.WriteString("  <stimuliModel>\n")
+	for _, t := range tasks {
+		fmt.Fprintf(&m, "    <stimuli xsi:type=\"am:PeriodicStimulus\" name=\"stimulus_T%d\">\n      %s\n      %s\n"+
+			"      <jitter xsi:type=\"am:TimeBoundaries\">\n        %s\n        %s\n      </jitter>\n    </stimuli>\n",
+			t.TaskID, amaltheaTime("offset", t.Offset), amaltheaTime("recurrence", t.Period), amaltheaTime("lowerBound", 0),
+			amaltheaTime("upperBound", t.Jitter))
+	}
+	m.WriteString("  </stimuliModel>\n")
+
+	var paths [][]int
+	if chain {
+		paths = chainPaths(vertices)
+	}
+	if len(paths) > 0 {
+		m.WriteString("  <eventModel>\n")
+		for _, t := range tasks {
+			for _, kind := range []string{"start", "terminate"} {
+				fmt.Fprintf(&m, "    <events xsi:type=\"am:ProcessEvent\" name=\"T%d_%s\" eventType=\"%s\" "+
+					"entity=\"%s\"/>\n", t.TaskID, kind, kind, amRef(fmt.Sprintf("T%d", t.TaskID), "Task"))
+			}
+		}
+		m.WriteString("  </eventModel>\n")
+	}
+
+	m.WriteString("  <constraintsModel>\n")
+	event := func(taskID int, kind string) string {
+		return amRef(fmt.Sprintf("T%d_%s", taskID, kind), "ProcessEvent")
+	}
+	for i, p := range paths {
+		fmt.Fprintf(&m, "    <eventChains name=\"chain%d\" stimulus=\"%s\" response=\"%s\">\n", i,
+			event(p[0], "start"), event(p[len(p)-1], "terminate"))
+		for j := 0; j+1 < len(p); j++ {
+			fmt.Fprintf(&m, "      <items xsi:type=\"am:EventChainContainer\" type=\"sequence\">\n"+
+				"        <eventChain name=\"chain%d_%d\" stimulus=\"%s\" response=\"%s\"/>\n      </items>\n", i, j,
+				event(p[j], "terminate"), event(p[j+1], "start"))
+		}
+		m.WriteString("    </eventChains>\n")
+	}
+	if dag {
+		// the edges of the DAGs, within the tasks of their vertices
+		ids := make(map[int]*common.Vertex, len(vertices))
+		for _, v := range vertices {
+			ids[v.VertexID] = v
+		}
+		for _, v := range vertices {
+			for _, s := range v.Successors {
+				fmt.Fprintf(&m, "    <runnableSequencingConstraints name=\"%s_%s\" orderType=\"successor\" "+
+					"processScope=\"%s\">\n      <runnableGroups runnables=\"%s\"/>\n"+
+					"      <runnableGroups runnables=\"%s\"/>\n    </runnableSequencingConstraints>\n",
+					runnableName(v), runnableName(ids[s]), amRef(fmt.Sprintf("T%d", v.TaskID), "Task"),
+					amRef(runnableName(v), "Runnable"), amRef(runnableName(ids[s]), "Runnable"))
+			}
+		}
+	}
+	for _, t := range tasks {
+		fmt.Fprintf(&m, "    <requirements xsi:type=\"am:ProcessRequirement\" name=\"deadline_T%d\" process=\"%s\" "+
+			"severity=\"Critical\">\n      <limit xsi:type=\"am:TimeRequirementLimit\" limitType=\"UpperLimit\" "+
+			"metric=\"ResponseTime\">\n        %s\n      </limit>\n    </requirements>\n", t.TaskID,
+			amRef(fmt.Sprintf("T%d", t.TaskID), "Task"), amaltheaTime("limitValue", t.Deadline))
+	}
+	m.WriteString("  </constraintsModel>\n")
+
+	m.WriteString("  <mappingModel>\n")
+	for c := 0; c < platform.Cores; c++ {
+		core := amRef(fmt.Sprintf("core%d", c), "ProcessingUnit")
+		fmt.Fprintf(&m, "    <schedulerAllocation scheduler=\"%s\" responsibility=\"%s\" executingPU=\"%s\"/>\n",
+			amRef(fmt.Sprintf("scheduler%d", c), "TaskScheduler"), core, core)
+	}
+	for i, t := range tasks {
+		fmt.Fprintf(&m, "    <taskAllocation task=\"%s\" scheduler=\"%s\" affinity=\"%s\"", amRef(fmt.Sprintf("T%d",
+			t.TaskID), "Task"), amRef(fmt.Sprintf("scheduler%d", t.PE), "TaskScheduler"),
+			amRef(fmt.Sprintf("core%d", t.PE), "ProcessingUnit"))
+		if platform.fixedPriority() {
+			fmt.Fprintf(&m, ">\n      <schedulingParameters priority=\"%d\"/>\n    </taskAllocation>\n",
+				priorities[platform.Priorities[i]])
+		} else {
+			m.WriteString("/>\n")
+		}
+	}
+	m.WriteString("  </mappingModel>\n</am:Amalthea>\n")
+	return os.WriteFile(path, []byte(m.String()), 0644)
+}
+
+// the elements of an Amalthea model that ReadAmalthea reads
+type amModel struct {
+	Tasks                []amProcess      `xml:"swModel>tasks"`
+	Runnables            []amProcess      `xml:"swModel>runnables"`
+	Structures           []amStructure    `xml:"hwModel>structures"`
+	Domains              []amDomain       `xml:"hwModel>domains"`
+	Stimuli              []amStimulus     `xml:"stimuliModel>stimuli"`
+	Events               []amEvent        `xml:"eventModel>events"`
+	EventChains          []amEventChain   `xml:"constraintsModel>eventChains"`
+	Sequencing           []amSequencing   `xml:"constraintsModel>runnableSequencingConstraints"`
+	Requirements         []amRequirement  `xml:"constraintsModel>requirements"`
+	SchedulerAllocations []amSchedulerMap `xml:"mappingModel>schedulerAllocation"`
+	TaskAllocations      []amTaskMap      `xml:"mappingModel>taskAllocation"`
+}
+
+type amProcess struct {
+	Name       string       `xml:"name,attr"`
+	Stimuli    string       `xml:"stimuli,attr"`
+	Properties []amProperty `xml:"customProperties"`
+	Items      []amItem     `xml:"activityGraph>items"`
+}
+
+// amProperty is a custom property of an element, e.g., the WCET of a task
+type amProperty struct {
+	Key   string `xml:"key,attr"`
+	Value *struct {
+		Type string `xml:"http://www.w3.org/2001/XMLSchema-instance type,attr"`
+		amTime
+	} `xml:"value"`
+}
+
+// amItem is an item of an activity graph, which can contain other items, e.g., in a group
+type amItem struct {
+	Type     string   `xml:"http://www.w3.org/2001/XMLSchema-instance type,attr"`
+	Runnable string   `xml:"runnable,attr"`
+	Default  *amValue `xml:"default"`
+	Items    []amItem `xml:"items"`
+}
+
+type amValue struct {
+	LowerBound string `xml:"lowerBound,attr"`
+	UpperBound string `xml:"upperBound,attr"`
+	Value      string `xml:"value,attr"`
+}
+
+type amStructure struct {
+	Modules    []amModule    `xml:"modules"`
+	Structures []amStructure `xml:"structures"`
+}
+
+type amModule struct {
+	Type            string `xml:"http://www.w3.org/2001/XMLSchema-instance type,attr"`
+	Name            string `xml:"name,attr"`
+	FrequencyDomain string `xml:"frequencyDomain,attr"`
+}
+
+type amDomain struct {
+	Name         string `xml:"name,attr"`
+	DefaultValue struct {
+		Value string `xml:"value,attr"`
+		Unit  string `xml:"unit,attr"`
+	} `xml:"defaultValue"`
+}
+
+type amTime struct {
+	Value string `xml:"value,attr"`
+	Unit  string `xml:"unit,attr"`
+}
+
+type amStimulus struct {
+	Type       string  `xml:"http://www.w3.org/2001/XMLSchema-instance type,attr"`
+	Name       string  `xml:"name,attr"`
+	Offset     *amTime `xml:"offset"`
+	Recurrence *amTime `xml:"recurrence"`
+	Jitter     *struct {
+		UpperBound *amTime `xml:"upperBound"`
+	} `xml:"jitter"`
+}
+
+type amEvent struct {
+	Name      string `xml:"name,attr"`
+	EventType string `xml:"eventType,attr"`
+	Entity    string `xml:"entity,attr"`
+}
+
+type amEventChain struct {
+	Stimulus string `xml:"stimulus,attr"`
+	Response string `xml:"response,attr"`
+	Items    []struct {
+		EventChain *amEventChain `xml:"eventChain"`
+	} `xml:"items"`
+}
+
+// amSequencing is a runnable sequencing constraint, where each group of runnables runs after the previous group
+type amSequencing struct {
+	Groups []struct {
+		Runnables string `xml:"runnables,attr"`
+	} `xml:"runnableGroups"`
+}
+
+type amRequirement struct {
+	Process string `xml:"process,attr"`
+	Limit   struct {
+		LimitType  string  `xml:"limitType,attr"`
+		Metric     string  `xml:"metric,attr"`
+		LimitValue *amTime `xml:"limitValue"`
+	} `xml:"limit"`
+}
+
+type amSchedulerMap struct {
+	Scheduler      string `xml:"scheduler,attr"`
+	Responsibility string `xml:"responsibility,attr"`
+	ExecutingPU    string `xml:"executingPU,attr"`
+}
+
+type amTaskMap struct {
+	Task       string `xml:"task,attr"`
+	Scheduler  string `xml:"scheduler,attr"`
+	Affinity   string `xml:"affinity,attr"`
+	Parameters *struct {
+		Priority string `xml:"priority,attr"`
+	} `xml:"schedulingParameters"`
+}
+
+// amName returns the name of the element of a reference, e.g., "T0" for "T0?type=Task". A list of references gives
+// its first one.
+func amName(ref string) string {
+	ref = strings.Fields(ref + " ")[0]
+	name, _, _ := strings.Cut(ref, "?")
+	if unescaped, err := url.PathUnescape(name); err == nil {
+		return unescaped
+	}
+	return name
+}
+
+// amNames returns the names of the elements of a list of references
+func amNames(refs string) []string {
+	var names []string
+	for _, ref := range strings.Fields(refs) {
+		names = append(names, amName(ref))
+	}
+	return names
+}
+
+// the microseconds in the units of time, and the ticks in a microsecond of the units of frequency
+var (
+	amMicroseconds = map[string]float64{"s": 1e6, "ms": 1e3, "us": 1, "ns": 1e-3, "ps": 1e-6}
+	amTicksPerUs   = map[string]float64{"Hz": 1e-6, "kHz": 1e-3, "MHz": 1, "GHz": 1e3}
+)
+
+// microseconds returns a time in microseconds, or 0 for no time
+func (t *amTime) microseconds() (int, error) {
+	if t == nil {
+		return 0, nil
+	}
+	value, err := strconv.ParseFloat(t.Value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q", t.Value)
+	}
+	unit, ok := amMicroseconds[t.Unit]
+	if !ok {
+		return 0, fmt.Errorf("unknown time unit %q", t.Unit)
+	}
+	return int(math.Round(value * unit)), nil
+}
+
+// ticks returns the best-case and the worst-case ticks of the ticks items of an activity graph
+func ticks(items []amItem) (float64, float64) {
+	var low, high float64
+	for _, item := range items {
+		if item.Type == "am:Ticks" && item.Default != nil {
+			l, errLow := strconv.ParseFloat(item.Default.LowerBound, 64)
+			h, errHigh := strconv.ParseFloat(item.Default.UpperBound, 64)
+			if errLow != nil || errHigh != nil {
+				// a constant number of ticks
+				l, _ = strconv.ParseFloat(item.Default.Value, 64)
+				h = l
+			}
+			low, high = low+l, high+h
+		}
+		l, h := ticks(item.Items)
+		low, high = low+l, high+h
+	}
+	return low, high
+}
+
+// runnableCalls returns the names of the runnables that an activity graph calls
+func runnableCalls(items []amItem) []string {
+	var calls []string
+	for _, item := range items {
+		if item.Runnable != "" {
+			calls = append(calls, amName(item.Runnable))
+		}
+		calls = append(calls, runnableCalls(item.Items)...)
+	}
+	return calls
+}
+
+// processingUnits returns the names of the processing units of the structures, and the frequency domain of each
+func processingUnits(structures []amStructure) ([]string, map[string]string) {
+	var names []string
+	domains := make(map[string]string)
+	for _, s := range structures {
+		for _, module := range s.Modules {
+			if module.Type == "am:ProcessingUnit" {
+				names = append(names, module.Name)
+				domains[module.Name] = amName(module.FrequencyDomain)
+			}
+		}
+		n, d := processingUnits(s.Structures)
+		names = append(names, n...)
+		for k, v := range d {
+			domains[k] = v
+		}
+	}
+	return names, domains
+}
+
+// property returns a time custom property of an element in microseconds, and whether the element has it
+func property(properties []amProperty, key string) (int, bool, error) {
+	for _, p := range properties {
+		if p.Key != key || p.Value == nil || p.Value.Type != "am:TimeObject" {
+			continue
+		}
+		t, err := p.Value.microseconds()
+		if err != nil {
+			return 0, false, fmt.Errorf("invalid %s: %v", key, err)
+		}
+		return t, true, nil
+	}
+	return 0, false, nil
+}
+
+// segments returns the segments of an event chain, or the chain itself if it has no segments
+func (c amEventChain) segments() []amEventChain {
+	var segments []amEventChain
+	for _, item := range c.Items {
+		if item.EventChain != nil {
+			segments = append(segments, item.EventChain.segments()...)
+		}
+	}
+	if len(segments) == 0 {
+		return []amEventChain{c}
+	}
+	return segments
+}
+
+// ReadAmalthea reads the tasks of an Amalthea (APP4MC) model, and the DAGs in its tasks or the chains of tasks of its
+// event chains, like WriteAmalthea writes them. The WCET and the BCET of a task are its "WCET" and "BCET" custom
+// properties, or the sums of the ticks of the runnables it calls on the frequency of its core (1 GHz by default), in
+// microseconds. The tasks need periodic stimuli. The deadline is the response-time requirement of a task or its
+// period, the core (PE) is the processing unit of its scheduler or its affinity, and the fixed priority is its
+// scheduling parameter turned to a smaller value for a higher priority. With runnable sequencing constraints, the
+// DAGs are a vertex for each runnable that a task calls, numbered in the order of the tasks and of the runnables of
+// the model, whose successors are the runnables of the constraints, or the next call of a task without constraints.
+// Otherwise, the chains are a vertex for each task whose successors are the next tasks of the event chains (see
+// generateTaskChain), or nil if there is no event chain of tasks.
+func ReadAmalthea(path string) (common.TaskSet, common.VertexSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	var model amModel
+	if err := xml.Unmarshal(data, &model); err != nil {
+		return nil, nil, fmt.Errorf("cannot read %s: %v", path, err)
+	}
+
+	// the frequencies of the cores
+	cores, coreDomains := processingUnits(model.Structures)
+	coreIndex := make(map[string]int, len(cores))
+	for i, core := range cores {
+		coreIndex[core] = i
+	}
+	domainTicks := make(map[string]float64)
+	for _, domain := range model.Domains {
+		value, err := strconv.ParseFloat(domain.DefaultValue.Value, 64)
+		if unit, ok := amTicksPerUs[domain.DefaultValue.Unit]; ok && err == nil && value > 0 {
+			domainTicks[domain.Name] = value * unit
+		}
+	}
+	ticksPerUs := func(core int) float64 {
+		if core < len(cores) {
+			if t, ok := domainTicks[coreDomains[cores[core]]]; ok {
+				return t
+			}
+		}
+		return amaltheaTicksPerUnit
+	}
+
+	runnables := make(map[string]amProcess, len(model.Runnables))
+	for _, r := range model.Runnables {
+		runnables[r.Name] = r
+	}
+	// the runnables of each task, in the order of the runnables of the model
+	calls := make([][]string, len(model.Tasks))
+	stimuli := make(map[string]amStimulus, len(model.Stimuli))
+	for _, s := range model.Stimuli {
+		stimuli[s.Name] = s
+	}
+	deadlines := make(map[string]*amTime)
+	for _, r := range model.Requirements {
+		if r.Limit.Metric == "ResponseTime" && r.Limit.LimitType == "UpperLimit" {
+			deadlines[amName(r.Process)] = r.Limit.LimitValue
+		}
+	}
+	schedulerCores := make(map[string]int)
+	for _, a := range model.SchedulerAllocations {
+		core := amName(a.ExecutingPU)
+		if core == "" {
+			core = amName(a.Responsibility)
+		}
+		if i, ok := coreIndex[core]; ok {
+			schedulerCores[amName(a.Scheduler)] = i
+		}
+	}
+	allocations := make(map[string]amTaskMap, len(model.TaskAllocations))
+	highest := 0
+	for _, a := range model.TaskAllocations {
+		allocations[amName(a.Task)] = a
+		if a.Parameters != nil {
+			p, _ := strconv.Atoi(a.Parameters.Priority)
+			highest = max(highest, p)
+		}
+	}
+
+	var tasks common.TaskSet
+	taskIDs := make(map[string]int, len(model.Tasks))
+	for i, process := range model.Tasks {
+		task := &common.Task{TaskID: i}
+		taskIDs[process.Name] = i
+
+		allocation, allocated := allocations[process.Name]
+		if allocated {
+			if core, ok := schedulerCores[amName(allocation.Scheduler)]; ok {
+				task.PE = core
+			} else if core, ok := coreIndex[amName(allocation.Affinity)]; ok {
+				task.PE = core
+			}
+			if allocation.Parameters != nil {
+				p, err := strconv.Atoi(allocation.Parameters.Priority)
+				if err != nil {
+					return nil, nil, fmt.Errorf("task %s has the invalid priority %q", process.Name,
+						allocation.Parameters.Priority)
+				}
+				task.Priority = highest + 1 - p
+			}
+		}
+
+		stimulus, ok := stimuli[amName(process.Stimuli)]
+		if !ok || stimulus.Type != "am:PeriodicStimulus" {
+			return nil, nil, fmt.Errorf("task %s has no periodic stimulus", process.Name)
+		}
+		if task.Period, err = stimulus.Recurrence.microseconds(); err != nil || task.Period <= 0 {
+			return nil, nil, fmt.Errorf("task %s has an invalid period: %v", process.Name, err)
+		}
+		if task.Offset, err = stimulus.Offset.microseconds(); err != nil {
+			return nil, nil, fmt.Errorf("task %s: %w", process.Name, err)
+		}
+		if stimulus.Jitter != nil {
+			if task.Jitter, err = stimulus.Jitter.UpperBound.microseconds(); err != nil {
+				return nil, nil, fmt.Errorf("task %s: %w", process.Name, err)
+			}
+		}
+		task.Deadline = task.Period
+		if deadline, ok := deadlines[process.Name]; ok && deadline != nil {
+			if task.Deadline, err = deadline.microseconds(); err != nil {
+				return nil, nil, fmt.Errorf("task %s: %w", process.Name, err)
+			}
+		}
+
+		var low, high float64
+		for _, name := range runnableCalls(process.Items) {
+			runnable, ok := runnables[name]
+			if !ok {
+				return nil, nil, fmt.Errorf("task %s calls the unknown runnable %s", process.Name, name)
+			}
+			l, h := ticks(runnable.Items)
+			low, high = low+l, high+h
+			if !slices.Contains(calls[i], name) {
+				calls[i] = append(calls[i], name)
+			}
+		}
+		task.BCET = int(math.Round(low / ticksPerUs(task.PE)))
+		task.WCET = int(math.Round(high / ticksPerUs(task.PE)))
+		// the times of the task, which are not the sums of the vertices of a DAG
+		for key, value := range map[string]*int{"BCET": &task.BCET, "WCET": &task.WCET} {
+			t, ok, err := property(process.Properties, key)
+			if err != nil {
+				return nil, nil, fmt.Errorf("task %s: %w", process.Name, err)
+			}
+			if ok {
+				*value = t
+			}
+		}
+		tasks = append(tasks, task)
+	}
+
+	if len(model.Sequencing) > 0 {
+		return tasks, runnableDAGs(model, tasks, calls, runnables, ticksPerUs), nil
+	}
+
+	// the chains of tasks go from the end of a task to the start of the next one
+	eventTasks := make(map[string]int, len(model.Events))
+	for _, event := range model.Events {
+		if id, ok := taskIDs[amName(event.Entity)]; ok {
+			eventTasks[event.Name] = id
+		}
+	}
+	successors := make(map[int][]int)
+	for _, c := range model.EventChains {
+		for _, segment := range c.segments() {
+			from, okFrom := eventTasks[amName(segment.Stimulus)]
+			to, okTo := eventTasks[amName(segment.Response)]
+			if okFrom && okTo && from != to && !slices.Contains(successors[from], to) {
+				successors[from] = append(successors[from], to)
+			}
+		}
+	}
+	if len(successors) == 0 {
+		return tasks, nil, nil
+	}
+	var vertices common.VertexSet
+	for _, t := range tasks {
+		s := successors[t.TaskID]
+		sort.Ints(s)
+		vertices = append(vertices, &common.Vertex{
+			TaskID:     t.TaskID,
+			VertexID:   t.TaskID,
+			Jitter:     t.Jitter,
+			BCET:       t.BCET,
+			WCET:       t.WCET,
+			Period:     t.Period,
+			Deadline:   t.Deadline,
+			PE:         t.PE,
+			Offset:     t.Offset,
+			Successors: s,
+		})
+	}
+	return tasks, vertices, nil
+}
+
+// runnableDAGs returns the DAGs in the tasks of a model, with a vertex for each runnable that a task calls, and the
+// edges of the runnable sequencing constraints between the runnables of the same task. The runnables of a task without
+// constraints run in the order of their calls.
+func runnableDAGs(model amModel, tasks common.TaskSet, calls [][]string, runnables map[string]amProcess,
+	ticksPerUs func(int) float64) common.VertexSet {
+	order := make(map[string]int, len(model.Runnables))
+	for i, r := range model.Runnables {
+		order[r.Name] = i
+	}
+	edges := make(map[string][]string)
+	for _, c := range model.Sequencing {
+		for i := 0; i+1 < len(c.Groups); i++ {
+			for _, from := range amNames(c.Groups[i].Runnables) {
+				edges[from] = append(edges[from], amNames(c.Groups[i+1].Runnables)...)
+			}
+		}
+	}
+
+	var vertices common.VertexSet
+	for i, t := range tasks {
+		names := slices.Clone(calls[i])
+		sort.SliceStable(names, func(a, b int) bool { return order[names[a]] < order[names[b]] })
+		ids := make(map[string]int, len(names))
+		for _, name := range names {
+			ids[name] = len(vertices) + len(ids)
+		}
+		constrained := false
+		for _, name := range names {
+			for _, next := range edges[name] {
+				if _, ok := ids[next]; ok {
+					constrained = true
+				}
+			}
+		}
+		for _, name := range names {
+			var successors []int
+			if constrained {
+				for _, next := range edges[name] {
+					if id, ok := ids[next]; ok && !slices.Contains(successors, id) {
+						successors = append(successors, id)
+					}
+				}
+				sort.Ints(successors)
+			} else if k := slices.Index(calls[i], name); k+1 < len(calls[i]) {
+				successors = []int{ids[calls[i][k+1]]}
+			}
+			low, high := ticks(runnables[name].Items)
+			vertices = append(vertices, &common.Vertex{
+				TaskID:     t.TaskID,
+				VertexID:   ids[name],
+				Jitter:     t.Jitter,
+				BCET:       int(math.Round(low / ticksPerUs(t.PE))),
+				WCET:       int(math.Round(high / ticksPerUs(t.PE))),
+				Period:     t.Period,
+				Deadline:   t.Deadline,
+				PE:         t.PE,
+				Offset:     t.Offset,
+				Successors: successors,
+			})
+		}
+	}
+	return vertices
+}
@@ -0,0 +1,64 @@
+package export
+
+import (
+	"path/filepath"
+	"reflect"
+	"task-generator/lib/common"
+	"testing"
+)
+
+// vertex creates a vertex of a task with its own times and the parameters of its task
+func vertex(task *common.Task, id, bcet, wcet int, successors ...int) *common.Vertex {
+	return &common.Vertex{TaskID: task.TaskID, VertexID: id, Jitter: task.Jitter, BCET: bcet, WCET: wcet,
+		Period: task.Period, Deadline: task.Deadline, PE: task.PE, Offset: task.Offset, Successors: successors}
+}
+
+func TestAmaltheaRoundTrip(t *testing.T) {
+	// the WCETs of the tasks are not the sums of the WCETs of their vertices, like the generated DAGs
+	t0 := &common.Task{TaskID: 0, Jitter: 100, BCET: 1218, WCET: 4464, Period: 10000, Deadline: 9000, PE: 1,
+		Priority: 1}
+	t1 := &common.Task{TaskID: 1, BCET: 50, WCET: 500, Period: 20000, Deadline: 20000, Offset: 300, Priority: 2}
+	tasks := common.TaskSet{t0, t1}
+
+	tests := []struct {
+		name     string
+		vertices common.VertexSet
+	}{
+		{"without DAG", nil},
+		{"fork-join DAGs", common.VertexSet{
+			vertex(t0, 0, 300, 1000, 1, 2),
+			vertex(t0, 1, 500, 2000, 3),
+			vertex(t0, 2, 400, 1458, 3),
+			vertex(t0, 3, 17, 3),
+			vertex(t1, 4, 50, 500),
+		}},
+		{"chain of tasks", common.VertexSet{
+			vertex(t0, 0, t0.BCET, t0.WCET, 1),
+			vertex(t1, 1, t1.BCET, t1.WCET),
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "model.amxmi")
+			platform := Platform{Cores: 2, Policy: "RM", Priorities: []int{1, 2}}
+			if err := WriteAmalthea(path, tasks, test.vertices, platform); err != nil {
+				t.Fatal(err)
+			}
+			readTasks, readVertices, err := ReadAmalthea(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(readTasks, tasks) {
+				for i := range readTasks {
+					t.Errorf("task %+v, want %+v", *readTasks[i], *tasks[i])
+				}
+			}
+			if !reflect.DeepEqual(readVertices, test.vertices) {
+				for i := range readVertices {
+					t.Errorf("vertex %+v", *readVertices[i])
+				}
+				t.Errorf("%d vertices, want %d", len(readVertices), len(test.vertices))
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"task-generator/lib/common"
+)
+
+// the range of the POSIX priorities of Cheddar, where a larger value is a higher priority
+const (
+	cheddarHighest = 255
+	cheddarLowest  = 1
+)
+
+// the elements of a Cheddar ADL file
+type cheddarModel struct {
+	XMLName       xml.Name              `xml:"cheddar"`
+	CoreUnits     []cheddarCoreUnit     `xml:"core_units>core_unit"`
+	Processors    []cheddarProcessor    `xml:"processors>mono_core_processor"`
+	AddressSpaces []cheddarAddressSpace `xml:"address_spaces>address_space"`
+	Tasks         []cheddarTask         `xml:"tasks>periodic_task"`
+}
+
+type cheddarScheduling struct {
+	SchedulerType  string `xml:"scheduling_parameters>scheduler_type"`
+	Quantum        int    `xml:"scheduling_parameters>quantum"`
+	PreemptiveType string `xml:"scheduling_parameters>preemptive_type"`
+	Capacity       int    `xml:"scheduling_parameters>capacity"`
+	Period         int    `xml:"scheduling_parameters>period"`
+	Priority       int    `xml:"scheduling_parameters>priority"`
+	StartTime      int    `xml:"scheduling_parameters>start_time"`
+}
+
+type cheddarCoreUnit struct {
+	ID                string            `xml:"id,attr"`
+	ObjectType        string            `xml:"object_type"`
+	Name              string            `xml:"name"`
+	Scheduling        cheddarScheduling `xml:"scheduling"`
+	Speed             string            `xml:"speed"`
+	L1CacheSystemName string            `xml:"l1_cache_system_name"`
+}
+
+type cheddarRef struct {
+	Ref string `xml:"ref,attr"`
+}
+
+type cheddarProcessor struct {
+	ID            string     `xml:"id,attr"`
+	ObjectType    string     `xml:"object_type"`
+	Name          string     `xml:"name"`
+	NetworkLink   string     `xml:"network_link"`
+	ProcessorType string     `xml:"processor_type"`
+	MigrationType string     `xml:"migration_type"`
+	Core          cheddarRef `xml:"core"`
+}
+
+type cheddarAddressSpace struct {
+	ID              string            `xml:"id,attr"`
+	ObjectType      string            `xml:"object_type"`
+	Name            string            `xml:"name"`
+	CPUName         string            `xml:"cpu_name"`
+	TextMemorySize  int               `xml:"text_memory_size"`
+	StackMemorySize int               `xml:"stack_memory_size"`
+	DataMemorySize  int               `xml:"data_memory_size"`
+	HeapMemorySize  int               `xml:"heap_memory_size"`
+	Scheduling      cheddarScheduling `xml:"scheduling"`
+}
+
+type cheddarTask struct {
+	ID                    string `xml:"id,attr"`
+	ObjectType            string `xml:"object_type"`
+	Name                  string `xml:"name"`
+	TaskType              string `xml:"task_type"`
+	CPUName               string `xml:"cpu_name"`
+	AddressSpaceName      string `xml:"address_space_name"`
+	Capacity              int    `xml:"capacity"`
+	Deadline              int    `xml:"deadline"`
+	StartTime             int    `xml:"start_time"`
+	Priority              int    `xml:"priority"`
+	BlockingTime          int    `xml:"blocking_time"`
+	Policy                string `xml:"policy"`
+	TextMemorySize        int    `xml:"text_memory_size"`
+	StackMemorySize       int    `xml:"stack_memory_size"`
+	Criticality           int    `xml:"criticality"`
+	ContextSwitchOverhead int    `xml:"context_switch_overhead"`
+	Period                int    `xml:"period"`
+	Jitter                int    `xml:"jitter"`
+	Every                 int    `xml:"every"`
+}
+
+// WriteCheddar writes the task set as a Cheddar ADL model with a processor for each core, on which the tasks of the
+// core (PE) are scheduled preemptively. The cores use the POSIX fixed priorities of the tasks, from 255 down in the
+// order of the fixed priorities of the platform, or EDF.
+func WriteCheddar(path string, tasks common.TaskSet, platform Platform) error {
+	if platform.Cores < 1 {
+		return fmt.Errorf("unknown number of cores for Cheddar")
+	}
+	scheduler, policy := "EARLIEST_DEADLINE_FIRST_PROTOCOL", "SCHED_OTHERS"
+	if platform.fixedPriority() {
+		scheduler, policy = "POSIX_1003_HIGHEST_PRIORITY_FIRST_PROTOCOL", "SCHED_FIFO"
+	}
+	priorities := descendingPriorities(platform.Priorities, cheddarHighest, cheddarLowest)
+
+	var model cheddarModel
+	id := 0
+	nextID := func() string {
+		id++
+		return fmt.Sprintf("id_%d", id)
+	}
+	for c := 0; c < platform.Cores; c++ {
+		core := cheddarCoreUnit{
+			ID:         nextID(),
+			ObjectType: "CORE_OBJECT_TYPE",
+			Name:       fmt.Sprintf("core%d", c),
+			Scheduling: cheddarScheduling{SchedulerType: scheduler, PreemptiveType: "PREEMPTIVE"},
+			Speed:      "1.00000",
+		}
+		model.CoreUnits = append(model.CoreUnits, core)
+		model.Processors = append(model.Processors, cheddarProcessor{
+			ID:            nextID(),
+			ObjectType:    "PROCESSOR_OBJECT_TYPE",
+			Name:          fmt.Sprintf("processor%d", c),
+			NetworkLink:   "No_Network",
+			ProcessorType: "MONOCORE_TYPE",
+			MigrationType: "NO_MIGRATION_TYPE",
+			Core:          cheddarRef{Ref: core.ID},
+		})
+		model.AddressSpaces = append(model.AddressSpaces, cheddarAddressSpace{
+			ID:         nextID(),
+			ObjectType: "ADDRESS_SPACE_OBJECT_TYPE",
+			Name:       fmt.Sprintf("address_space%d", c),
+			CPUName:    fmt.Sprintf("processor%d", c),
+			Scheduling: cheddarScheduling{SchedulerType: "NO_SCHEDULING_PROTOCOL", PreemptiveType: "PREEMPTIVE"},
+		})
+	}
+
+	for i, t := range tasks {
+		if t.PE < 0 || t.PE >= platform.Cores {
+			return fmt.Errorf("task %d is mapped to core %d of %d cores", t.TaskID, t.PE, platform.Cores)
+		}
+		priority := cheddarLowest
+		if platform.fixedPriority() {
+			priority = priorities[platform.Priorities[i]]
+		}
+		model.Tasks = append(model.Tasks, cheddarTask{
+			ID:               nextID(),
+			ObjectType:       "TASK_OBJECT_TYPE",
+			Name:             fmt.Sprintf("T%d", t.TaskID),
+			TaskType:         "PERIODIC_TYPE",
+			CPUName:          fmt.Sprintf("processor%d", t.PE),
+			AddressSpaceName: fmt.Sprintf("address_space%d", t.PE),
+			Capacity:         t.WCET,
+			Deadline:         t.Deadline,
+			StartTime:        t.Offset,
+			Priority:         priority,
+			Policy:           policy,
+			Criticality:      t.Criticality,
+			Period:           t.Period,
+			Jitter:           t.Jitter,
+		})
+	}
+
+	data, err := xml.MarshalIndent(model, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append([]byte("<?xml version=\"1.0\" standalone=\"yes\"?>\n"), append(data, '\n')...),
+		0644)
+}
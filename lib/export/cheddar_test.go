@@ -0,0 +1,76 @@
+package export
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"task-generator/lib/common"
+	"testing"
+)
+
+// readCheddar reads back a Cheddar ADL model
+func readCheddar(t *testing.T, path string) cheddarModel {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var model cheddarModel
+	if err := xml.Unmarshal(data, &model); err != nil {
+		t.Fatal(err)
+	}
+	return model
+}
+
+func TestWriteCheddar(t *testing.T) {
+	tasks := common.TaskSet{
+		{TaskID: 0, Jitter: 10, BCET: 100, WCET: 250, Period: 1000, Deadline: 800, PE: 1},
+		{TaskID: 1, BCET: 50, WCET: 150, Period: 2000, Deadline: 2000, Offset: 300, Criticality: 1},
+		{TaskID: 2, BCET: 50, WCET: 100, Period: 4000, Deadline: 4000, PE: 1},
+	}
+	path := filepath.Join(t.TempDir(), "cheddar.xml")
+
+	// the tasks of the same fixed priority share their POSIX priority
+	if err := WriteCheddar(path, tasks, Platform{Cores: 2, Policy: "RM", Priorities: []int{1, 2, 2}}); err != nil {
+		t.Fatal(err)
+	}
+	model := readCheddar(t, path)
+	if len(model.CoreUnits) != 2 || len(model.Processors) != 2 || len(model.AddressSpaces) != 2 ||
+		len(model.Tasks) != 3 {
+		t.Fatalf("%d cores, %d processors, %d address spaces, and %d tasks", len(model.CoreUnits),
+			len(model.Processors), len(model.AddressSpaces), len(model.Tasks))
+	}
+	for c, core := range model.CoreUnits {
+		if core.Scheduling.SchedulerType != "POSIX_1003_HIGHEST_PRIORITY_FIRST_PROTOCOL" ||
+			model.Processors[c].Core.Ref != core.ID {
+			t.Errorf("core %d is %+v of the processor %+v", c, core, model.Processors[c])
+		}
+	}
+	first, second, third := model.Tasks[0], model.Tasks[1], model.Tasks[2]
+	if first.CPUName != "processor1" || first.AddressSpaceName != "address_space1" || first.Capacity != 250 ||
+		first.Deadline != 800 || first.Period != 1000 || first.Jitter != 10 || first.Priority != 255 ||
+		first.Policy != "SCHED_FIFO" {
+		t.Errorf("the first task is %+v", first)
+	}
+	if second.CPUName != "processor0" || second.StartTime != 300 || second.Criticality != 1 ||
+		second.Priority != 254 || third.Priority != 254 {
+		t.Errorf("the second task is %+v and the third %+v", second, third)
+	}
+
+	if err := WriteCheddar(path, tasks, Platform{Cores: 2, Policy: "EDF"}); err != nil {
+		t.Fatal(err)
+	}
+	model = readCheddar(t, path)
+	if model.CoreUnits[0].Scheduling.SchedulerType != "EARLIEST_DEADLINE_FIRST_PROTOCOL" ||
+		model.Tasks[0].Policy != "SCHED_OTHERS" || model.Tasks[0].Priority != cheddarLowest {
+		t.Errorf("the EDF core is %+v with the task %+v", model.CoreUnits[0], model.Tasks[0])
+	}
+
+	// all the tasks are on the cores of the platform
+	if err := WriteCheddar(path, tasks, Platform{Cores: 1, Policy: "EDF"}); err == nil {
+		t.Error("a task is written on a core that does not exist")
+	}
+	if err := WriteCheddar(path, tasks, Platform{Policy: "EDF"}); err == nil {
+		t.Error("the model is written without cores")
+	}
+}
@@ -0,0 +1,55 @@
+package export
+
+import (
+	"sort"
+	"task-generator/lib/common"
+)
+
+// Platform is the platform and the scheduling policy of an exported task set
+type Platform struct {
+	// Cores is the number of cores, and the PE of each task is its core
+	Cores int
+	// Policy is the priority assignment of the task set, e.g., "RM" or "EDF"
+	Policy string
+	// Priorities are the fixed priorities of the tasks, where a smaller value is a higher priority, or nil for EDF
+	Priorities []int
+	// Duration is the length of the schedule to run, e.g., the hyperperiod
+	Duration int
+}
+
+// duration returns the length of the schedule of the platform, or the feasibility interval of the task set
+func (p Platform) duration(tasks common.TaskSet) int {
+	if p.Duration > 0 {
+		return p.Duration
+	}
+	hyperperiod := tasks.HyperPeriod()
+	if hyperperiod <= 0 {
+		// the hyperperiod is too large, so a few periods of the longest task are run instead
+		maxPeriod := 0
+		for _, t := range tasks {
+			maxPeriod = max(maxPeriod, t.Period)
+		}
+		return 10 * maxPeriod
+	}
+	return common.FeasibilityInterval(hyperperiod, tasks.MaxOffset())
+}
+
+// fixedPriority reports whether the tasks have fixed priorities
+func (p Platform) fixedPriority() bool {
+	return p.Priorities != nil
+}
+
+// descendingPriorities returns the priority of each fixed priority in a tool where a larger value is a higher
+// priority, from highest down by priority level. With more levels than the tool has, the lowest levels share the
+// lowest priority.
+func descendingPriorities(priorities []int, highest, lowest int) map[int]int {
+	levels := append([]int(nil), priorities...)
+	sort.Ints(levels)
+	descending := make(map[int]int)
+	for _, p := range levels {
+		if _, ok := descending[p]; !ok {
+			descending[p] = max(highest-len(descending), lowest)
+		}
+	}
+	return descending
+}
@@ -0,0 +1,118 @@
+package export
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"task-generator/lib/common"
+)
+
+// the range of the priorities of MAST, where a larger value is a higher priority
+const (
+	mastHighest = 32767
+	mastLowest  = 1
+)
+
+// mastTime writes a time of the task set as a MAST time
+func mastTime(t int) string {
+	return fmt.Sprintf("%d.00", t)
+}
+
+// WriteMAST writes the task set as a MAST model with a processor for each core, and a transaction for each task that
+// is released periodically with its jitter and offset, runs on the core of the task (PE), and has a hard deadline.
+// The processors use the fixed priorities of the platform, preassigned from 32767 down in their order, or EDF.
+func WriteMAST(path string, tasks common.TaskSet, platform Platform) error {
+	if platform.Cores < 1 {
+		return fmt.Errorf("unknown number of cores for MAST")
+	}
+	priorities := descendingPriorities(platform.Priorities, mastHighest, mastLowest)
+
+	var model strings.Builder
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	fmt.Fprintf(&model, "Model (\n   Model_Name => %s);\n\n", strings.NewReplacer(".", "_", "-", "_").Replace(name))
+
+	for c := 0; c < platform.Cores; c++ {
+		fmt.Fprintf(&model, `Processing_Resource (
+   Type                   => Regular_Processor,
+   Name                   => cpu%d,
+   Max_Interrupt_Priority => %d,
+   Min_Interrupt_Priority => %d,
+   Worst_ISR_Switch       => 0.00,
+   Avg_ISR_Switch         => 0.00,
+   Best_ISR_Switch        => 0.00,
+   Speed_Factor           => 1.00);
+
+`, c, mastHighest, mastLowest)
+		policy := "EDF,\n        Worst_Context_Switch => 0.00,\n        Avg_Context_Switch   => 0.00,\n" +
+			"        Best_Context_Switch  => 0.00"
+		if platform.fixedPriority() {
+			policy = fmt.Sprintf("Fixed_Priority,\n        Worst_Context_Switch => 0.00,\n"+
+				"        Avg_Context_Switch   => 0.00,\n        Best_Context_Switch  => 0.00,\n"+
+				"        Max_Priority         => %d,\n        Min_Priority         => %d", mastHighest, mastLowest)
+		}
+		fmt.Fprintf(&model, `Scheduler (
+   Type   => Primary_Scheduler,
+   Name   => cpu%d,
+   Host   => cpu%d,
+   Policy =>
+      ( Type                 => %s));
+
+`, c, c, policy)
+	}
+
+	for i, t := range tasks {
+		if t.PE < 0 || t.PE >= platform.Cores {
+			return fmt.Errorf("task %d is mapped to core %d of %d cores", t.TaskID, t.PE, platform.Cores)
+		}
+		parameters := fmt.Sprintf("EDF_Policy,\n        Deadline     => %s,\n        Preassigned  => NO",
+			mastTime(t.Deadline))
+		if platform.fixedPriority() {
+			parameters = fmt.Sprintf("Fixed_Priority_Policy,\n        The_Priority => %d,\n"+
+				"        Preassigned  => YES", priorities[platform.Priorities[i]])
+		}
+		fmt.Fprintf(&model, `Scheduling_Server (
+   Type                    => Regular,
+   Name                    => t%d,
+   Server_Sched_Parameters =>
+      ( Type         => %s),
+   Scheduler               => cpu%d);
+
+Operation (
+   Type                      => Simple,
+   Name                      => o%d,
+   Worst_Case_Execution_Time => %s,
+   Avg_Case_Execution_Time   => %s,
+   Best_Case_Execution_Time  => %s);
+
+Transaction (
+   Type            => Regular,
+   Name            => tr%d,
+   External_Events =>
+      ( ( Type       => Periodic,
+          Name       => e%d,
+          Period     => %s,
+          Max_Jitter => %s,
+          Phase      => %s)),
+   Internal_Events =>
+      ( ( Type                => Regular,
+          Name                => d%d,
+          Timing_Requirements =>
+            ( Type             => Hard_Global_Deadline,
+              Deadline         => %s,
+              Referenced_Event => e%d))),
+   Event_Handlers  =>
+      ( ( Type               => Activity,
+          Input_Event        => e%d,
+          Output_Event       => d%d,
+          Activity_Operation => o%d,
+          Activity_Server    => t%d)));
+
+`, t.TaskID, parameters, t.PE,
+			t.TaskID, mastTime(t.WCET), mastTime((t.BCET+t.WCET)/2), mastTime(t.BCET),
+			t.TaskID, t.TaskID, mastTime(t.Period), mastTime(t.Jitter), mastTime(t.Offset),
+			t.TaskID, mastTime(t.Deadline), t.TaskID,
+			t.TaskID, t.TaskID, t.TaskID, t.TaskID)
+	}
+	return os.WriteFile(path, []byte(model.String()), 0644)
+}
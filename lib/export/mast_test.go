@@ -0,0 +1,64 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"task-generator/lib/common"
+	"testing"
+)
+
+// readMAST reads back a MAST model with its spaces collapsed, so it can be searched for its fields
+func readMAST(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Join(strings.Fields(string(data)), " ")
+}
+
+func TestWriteMAST(t *testing.T) {
+	tasks := common.TaskSet{
+		{TaskID: 0, Jitter: 10, BCET: 100, WCET: 250, Period: 1000, Deadline: 800, PE: 1},
+		{TaskID: 1, BCET: 50, WCET: 150, Period: 2000, Deadline: 2000, Offset: 300},
+	}
+	path := filepath.Join(t.TempDir(), "task-set.0.txt")
+
+	if err := WriteMAST(path, tasks, Platform{Cores: 2, Policy: "DM", Priorities: []int{1, 2}}); err != nil {
+		t.Fatal(err)
+	}
+	model := readMAST(t, path)
+	for _, want := range []string{
+		// the name of the model is a valid identifier
+		"Model_Name => task_set_0",
+		"Name => cpu0, Host => cpu0, Policy => ( Type => Fixed_Priority,",
+		"Name => cpu1, Host => cpu1, Policy => ( Type => Fixed_Priority,",
+		"Name => t0, Server_Sched_Parameters => ( Type => Fixed_Priority_Policy, The_Priority => 32767, " +
+			"Preassigned => YES), Scheduler => cpu1",
+		"The_Priority => 32766, Preassigned => YES), Scheduler => cpu0",
+		"Name => o0, Worst_Case_Execution_Time => 250.00, Avg_Case_Execution_Time => 175.00, " +
+			"Best_Case_Execution_Time => 100.00",
+		"Name => e0, Period => 1000.00, Max_Jitter => 10.00, Phase => 0.00",
+		"Name => e1, Period => 2000.00, Max_Jitter => 0.00, Phase => 300.00",
+		"Type => Hard_Global_Deadline, Deadline => 800.00, Referenced_Event => e0",
+		"Input_Event => e1, Output_Event => d1, Activity_Operation => o1, Activity_Server => t1",
+	} {
+		if !strings.Contains(model, want) {
+			t.Errorf("the model has no %q", want)
+		}
+	}
+
+	if err := WriteMAST(path, tasks, Platform{Cores: 2, Policy: "EDF"}); err != nil {
+		t.Fatal(err)
+	}
+	model = readMAST(t, path)
+	if strings.Contains(model, "Fixed_Priority") ||
+		!strings.Contains(model, "Type => EDF_Policy, Deadline => 800.00, Preassigned => NO), Scheduler => cpu1") {
+		t.Errorf("the EDF model has fixed priorities or no deadlines:\n%s", model)
+	}
+
+	if err := WriteMAST(path, tasks, Platform{Cores: 1, Policy: "EDF"}); err == nil {
+		t.Error("a task is written on a core that does not exist")
+	}
+}
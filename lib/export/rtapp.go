@@ -0,0 +1,261 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"task-generator/lib/common"
+)
+
+// the scheduling policies of rt-app
+const (
+	SchedDeadline = "SCHED_DEADLINE"
+	SchedFIFO     = "SCHED_FIFO"
+)
+
+// the range of the real-time priorities of SCHED_FIFO that the tasks get, where a larger value is a higher priority.
+// 99 is left to the threads of the kernel.
+const (
+	fifoHighest = 98
+	fifoLowest  = 1
+)
+
+// rtAppMicroseconds is the number of microseconds of rt-app in a time unit of the task set
+const rtAppMicroseconds = 1
+
+// object is a JSON object that keeps the order of its keys, because rt-app runs the events of a thread in the order
+// of their keys
+type object []field
+
+type field struct {
+	key   string
+	value interface{}
+}
+
+// MarshalJSON writes the keys in their order
+func (o object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, f := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(f.key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(f.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// RTAppPolicy returns the policy of the rt-app threads of a priority policy: SCHED_DEADLINE for EDF, and SCHED_FIFO
+// for the fixed priorities
+func RTAppPolicy(policy string) string {
+	if policy == "EDF" {
+		return SchedDeadline
+	}
+	return SchedFIFO
+}
+
+// rtAppThread is a thread of rt-app, which runs its events once in each period
+type rtAppThread struct {
+	name string
+	// task is the index of the task of the thread
+	task     int
+	pe       int
+	wcet     int
+	period   int
+	deadline int
+	offset   int
+	// before and after are the barriers before and after the execution of the thread
+	before, after []string
+}
+
+// WriteRTApp writes the task set as an rt-app workload with a thread for each task, which runs for its WCET in each
+// period. With the vertices of the DAGs of the task set, there is a thread for each vertex instead, and each edge of
+// a DAG is a barrier that the successor waits at before it runs, and that the predecessor passes after it runs. The
+// barriers of a thread are taken in the topological order of the vertices, so the threads never wait for each other
+// in a cycle. The threads use SCHED_FIFO with the fixed priorities of the platform, or SCHED_DEADLINE for EDF with the
+// WCET as the runtime. The threads of SCHED_FIFO run on the core of their task (PE), while Linux only admits
+// SCHED_DEADLINE threads that may run on all the cores of their root domain, so those are not pinned. The time unit of
+// the task set is one microsecond of rt-app, and the release jitter is not exported.
+func WriteRTApp(path string, tasks common.TaskSet, vertices common.VertexSet, platform Platform) error {
+	policy := RTAppPolicy(platform.Policy)
+	if policy == SchedFIFO && !platform.fixedPriority() {
+		return fmt.Errorf("SCHED_FIFO needs the fixed priorities of the tasks")
+	}
+	threads, err := rtAppThreads(tasks, vertices)
+	if err != nil {
+		return err
+	}
+	fifo := descendingPriorities(platform.Priorities, fifoHighest, fifoLowest)
+
+	threadObjects := object{}
+	for _, thread := range threads {
+		t := object{
+			{"instance", 1},
+			{"loop", -1},
+			{"policy", policy},
+		}
+		if policy == SchedFIFO {
+			t = append(t, field{"priority", fifo[platform.Priorities[thread.task]]})
+			t = append(t, field{"cpus", []int{thread.pe}})
+		} else {
+			// the deadline of SCHED_DEADLINE cannot be larger than the period
+			t = append(t, field{"dl-runtime", thread.wcet * rtAppMicroseconds},
+				field{"dl-period", thread.period * rtAppMicroseconds},
+				field{"dl-deadline", min(thread.deadline, thread.period) * rtAppMicroseconds})
+		}
+		if thread.offset > 0 {
+			t = append(t, field{"delay", thread.offset * rtAppMicroseconds})
+		}
+		n := 0
+		for _, barrier := range thread.before {
+			t = append(t, field{fmt.Sprintf("barrier%d", n), barrier})
+			n++
+		}
+		t = append(t, field{"run", thread.wcet * rtAppMicroseconds})
+		for _, barrier := range thread.after {
+			t = append(t, field{fmt.Sprintf("barrier%d", n), barrier})
+			n++
+		}
+		t = append(t, field{"timer", object{
+			{"ref", thread.name},
+			{"period", thread.period * rtAppMicroseconds},
+			{"mode", "absolute"},
+		}})
+		threadObjects = append(threadObjects, field{thread.name, t})
+	}
+
+	// rt-app runs for whole seconds
+	seconds := (int64(platform.duration(tasks))*rtAppMicroseconds + 999999) / 1000000
+	workload := object{
+		{"tasks", threadObjects},
+		{"global", object{
+			{"duration", max(seconds, 1)},
+			{"calibration", "CPU0"},
+			{"default_policy", "SCHED_OTHER"},
+			{"pi_enabled", false},
+			{"lock_pages", true},
+			{"logdir", "./"},
+			{"log_basename", "rt-app"},
+		}},
+	}
+	data, err := json.MarshalIndent(workload, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// rtAppThreads returns a thread for each task, or for each vertex of the DAGs with the barriers of their edges
+func rtAppThreads(tasks common.TaskSet, vertices common.VertexSet) ([]rtAppThread, error) {
+	var threads []rtAppThread
+	if len(vertices) == 0 {
+		for i, t := range tasks {
+			threads = append(threads, rtAppThread{
+				name:     fmt.Sprintf("task%d", t.TaskID),
+				task:     i,
+				pe:       t.PE,
+				wcet:     t.WCET,
+				period:   t.Period,
+				deadline: t.Deadline,
+				offset:   t.Offset,
+			})
+		}
+		return threads, nil
+	}
+
+	taskIndex := make(map[int]int, len(tasks))
+	for i, t := range tasks {
+		taskIndex[t.TaskID] = i
+	}
+	order, err := topologicalOrder(vertices)
+	if err != nil {
+		return nil, err
+	}
+	threadOf := make(map[int]int, len(vertices))
+	for _, v := range order {
+		vertex := vertices[v]
+		i, ok := taskIndex[vertex.TaskID]
+		if !ok {
+			return nil, fmt.Errorf("vertex %d belongs to the unknown task %d", vertex.VertexID, vertex.TaskID)
+		}
+		threadOf[vertex.VertexID] = len(threads)
+		threads = append(threads, rtAppThread{
+			name:     fmt.Sprintf("task%d_v%d", vertex.TaskID, vertex.VertexID),
+			task:     i,
+			pe:       vertex.PE,
+			wcet:     vertex.WCET,
+			period:   vertex.Period,
+			deadline: vertex.Deadline,
+			offset:   vertex.Offset,
+		})
+	}
+	// the threads are in the topological order, so both the barriers before and after a thread are in that order
+	for _, v := range order {
+		vertex := vertices[v]
+		from := threadOf[vertex.VertexID]
+		successors := make([]int, 0, len(vertex.Successors))
+		for _, s := range vertex.Successors {
+			successors = append(successors, threadOf[s])
+		}
+		sort.Ints(successors)
+		for _, to := range successors {
+			barrier := fmt.Sprintf("edge_v%d_v%d", vertex.VertexID, vertices[order[to]].VertexID)
+			threads[from].after = append(threads[from].after, barrier)
+			threads[to].before = append(threads[to].before, barrier)
+		}
+	}
+	return threads, nil
+}
+
+// topologicalOrder returns the indices of the vertices in a topological order of their edges
+func topologicalOrder(vertices common.VertexSet) ([]int, error) {
+	index := make(map[int]int, len(vertices))
+	for i, vertex := range vertices {
+		index[vertex.VertexID] = i
+	}
+	inDegree := make([]int, len(vertices))
+	for _, vertex := range vertices {
+		for _, s := range vertex.Successors {
+			j, ok := index[s]
+			if !ok {
+				return nil, fmt.Errorf("vertex %d has the unknown successor %d", vertex.VertexID, s)
+			}
+			inDegree[j]++
+		}
+	}
+	var order, ready []int
+	for i, d := range inDegree {
+		if d == 0 {
+			ready = append(ready, i)
+		}
+	}
+	for len(ready) > 0 {
+		i := ready[0]
+		ready = ready[1:]
+		order = append(order, i)
+		for _, s := range vertices[i].Successors {
+			j := index[s]
+			inDegree[j]--
+			if inDegree[j] == 0 {
+				ready = append(ready, j)
+			}
+		}
+	}
+	if len(order) != len(vertices) {
+		return nil, fmt.Errorf("the DAG has a cycle")
+	}
+	return order, nil
+}
@@ -0,0 +1,144 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"task-generator/lib/common"
+	"testing"
+)
+
+// readRTApp reads back the threads of an rt-app workload and the order of the keys of each thread
+func readRTApp(t *testing.T, path string) (map[string]map[string]interface{}, map[string][]string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var workload struct {
+		Tasks map[string]map[string]interface{} `json:"tasks"`
+	}
+	if err := json.Unmarshal(data, &workload); err != nil {
+		t.Fatal(err)
+	}
+	var raw struct {
+		Tasks map[string]json.RawMessage `json:"tasks"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatal(err)
+	}
+	order := make(map[string][]string)
+	for name, thread := range raw.Tasks {
+		decoder := json.NewDecoder(bytes.NewReader(thread))
+		decoder.Token()
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				t.Fatal(err)
+			}
+			order[name] = append(order[name], key.(string))
+			var value json.RawMessage
+			if err := decoder.Decode(&value); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	return workload.Tasks, order
+}
+
+func TestWriteRTApp(t *testing.T) {
+	tasks := common.TaskSet{
+		{TaskID: 0, BCET: 1000, WCET: 2500, Period: 10000, Deadline: 8000, PE: 1},
+		{TaskID: 1, BCET: 500, WCET: 1500, Period: 20000, Deadline: 25000, Offset: 3000},
+	}
+	path := filepath.Join(t.TempDir(), "rt-app.json")
+
+	// a smaller fixed priority is a higher priority of SCHED_FIFO
+	if err := WriteRTApp(path, tasks, nil, Platform{Cores: 2, Policy: "RM", Priorities: []int{1, 2}}); err != nil {
+		t.Fatal(err)
+	}
+	threads, _ := readRTApp(t, path)
+	first, second := threads["task0"], threads["task1"]
+	if first["policy"] != SchedFIFO || first["priority"] != 98.0 || first["run"] != 2500.0 || first["delay"] != nil ||
+		!reflect.DeepEqual(first["cpus"], []interface{}{1.0}) {
+		t.Errorf("the first thread is %v", first)
+	}
+	timer := map[string]interface{}{"ref": "task1", "period": 20000.0, "mode": "absolute"}
+	if second["priority"] != 97.0 || second["delay"] != 3000.0 || !reflect.DeepEqual(second["timer"], timer) {
+		t.Errorf("the second thread is %v", second)
+	}
+
+	// the runtime of SCHED_DEADLINE is the WCET, and its deadline is at most the period
+	if err := WriteRTApp(path, tasks, nil, Platform{Cores: 2, Policy: "EDF"}); err != nil {
+		t.Fatal(err)
+	}
+	threads, _ = readRTApp(t, path)
+	first, second = threads["task0"], threads["task1"]
+	if first["policy"] != SchedDeadline || first["dl-runtime"] != 2500.0 || first["dl-period"] != 10000.0 ||
+		first["dl-deadline"] != 8000.0 || first["cpus"] != nil || first["priority"] != nil {
+		t.Errorf("the first thread is %v", first)
+	}
+	if second["dl-deadline"] != 20000.0 {
+		t.Errorf("the deadline of the second thread is %v instead of its period", second["dl-deadline"])
+	}
+
+	if err := WriteRTApp(path, tasks, nil, Platform{Cores: 2, Policy: "DM"}); err == nil {
+		t.Error("SCHED_FIFO is written without priorities")
+	}
+}
+
+func TestWriteRTAppDAG(t *testing.T) {
+	task := &common.Task{TaskID: 0, BCET: 40, WCET: 100, Period: 1000, Deadline: 1000}
+	vertices := common.VertexSet{
+		vertex(task, 3, 5, 10),
+		vertex(task, 0, 5, 20, 2, 1),
+		vertex(task, 1, 10, 30, 3),
+		vertex(task, 2, 10, 40, 3),
+	}
+	path := filepath.Join(t.TempDir(), "rt-app.json")
+	platform := Platform{Cores: 1, Policy: "RM", Priorities: []int{1}}
+	if err := WriteRTApp(path, common.TaskSet{task}, vertices, platform); err != nil {
+		t.Fatal(err)
+	}
+	threads, order := readRTApp(t, path)
+	if len(threads) != 4 {
+		t.Fatalf("%d threads instead of 4", len(threads))
+	}
+
+	// each edge is a barrier after the predecessor runs and before the successor runs, and the barriers are in the
+	// topological order of the vertices, where v2 comes before v1 as the first successor of v0
+	events := func(name string) []string {
+		var events []string
+		for _, key := range order[name] {
+			if key == "run" {
+				events = append(events, key)
+			} else if strings.HasPrefix(key, "barrier") {
+				events = append(events, threads[name][key].(string))
+			}
+		}
+		return events
+	}
+	want := map[string][]string{
+		"task0_v0": {"run", "edge_v0_v2", "edge_v0_v1"},
+		"task0_v1": {"edge_v0_v1", "run", "edge_v1_v3"},
+		"task0_v2": {"edge_v0_v2", "run", "edge_v2_v3"},
+		"task0_v3": {"edge_v2_v3", "edge_v1_v3", "run"},
+	}
+	for name, w := range want {
+		if e := events(name); !reflect.DeepEqual(e, w) {
+			t.Errorf("the events of %s are %v instead of %v", name, e, w)
+		}
+	}
+	if threads["task0_v2"]["run"] != 40.0 {
+		t.Errorf("the vertex runs for %v instead of its WCET", threads["task0_v2"]["run"])
+	}
+
+	// a cycle cannot be run
+	vertices[0].Successors = []int{0}
+	if err := WriteRTApp(path, common.TaskSet{task}, vertices, platform); err == nil {
+		t.Error("the DAG with a cycle is written")
+	}
+}
@@ -0,0 +1,181 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"task-generator/lib/common"
+)
+
+// simsoUnitsPerMs is the number of time units of the task set in a millisecond of SimSo, where a time unit is one
+// microsecond as in the other exports
+const simsoUnitsPerMs = 1000
+
+// simsoCyclesPerMs is the number of cycles of a millisecond of SimSo, so a time unit of the task set is 1000 cycles
+const simsoCyclesPerMs = 1000000
+
+// the schedulers of SimSo
+const (
+	// SimSoRM and SimSoEDF are the partitioned RM and EDF schedulers, which schedule the tasks of each core on their own
+	SimSoRM  = "simso.schedulers.P_RM"
+	SimSoEDF = "simso.schedulers.P_EDF"
+	// SimSoFP schedules the tasks globally by their "priority" field, where a larger value is a higher priority
+	SimSoFP = "simso.schedulers.FP"
+)
+
+// the elements of a SimSo configuration file
+type simsoSimulation struct {
+	XMLName     xml.Name        `xml:"simulation"`
+	Duration    int64           `xml:"duration,attr"`
+	CyclesPerMs int             `xml:"cycles_per_ms,attr"`
+	ETM         string          `xml:"etm,attr"`
+	Sched       simsoSched      `xml:"sched"`
+	Caches      simsoCaches     `xml:"caches"`
+	Processors  simsoProcessors `xml:"processors"`
+	Tasks       simsoTasks      `xml:"tasks"`
+}
+
+type simsoSched struct {
+	Class             string `xml:"class,attr"`
+	Overhead          int    `xml:"overhead,attr"`
+	OverheadActivate  int    `xml:"overhead_activate,attr"`
+	OverheadTerminate int    `xml:"overhead_terminate,attr"`
+}
+
+type simsoCaches struct {
+	MemoryAccessTime int `xml:"memory_access_time,attr"`
+}
+
+type simsoProcessors struct {
+	MigrationOverhead int              `xml:"migration_overhead,attr"`
+	Processors        []simsoProcessor `xml:"processor"`
+}
+
+type simsoProcessor struct {
+	Name       string  `xml:"name,attr"`
+	ID         int     `xml:"id,attr"`
+	CSOverhead int     `xml:"cs_overhead,attr"`
+	CLOverhead int     `xml:"cl_overhead,attr"`
+	Speed      float64 `xml:"speed,attr"`
+}
+
+type simsoTasks struct {
+	Fields []simsoField `xml:"field"`
+	Tasks  []simsoTask  `xml:"task"`
+}
+
+type simsoField struct {
+	Name string `xml:"name,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type simsoTask struct {
+	ID                  int        `xml:"id,attr"`
+	Name                string     `xml:"name,attr"`
+	TaskType            string     `xml:"task_type,attr"`
+	AbortOnMiss         string     `xml:"abort_on_miss,attr"`
+	Period              float64    `xml:"period,attr"`
+	ActivationDate      float64    `xml:"activationDate,attr"`
+	ListActivationDates string     `xml:"list_activation_dates,attr"`
+	Deadline            float64    `xml:"deadline,attr"`
+	BaseCPI             float64    `xml:"base_cpi,attr"`
+	Instructions        int        `xml:"instructions,attr"`
+	Mix                 float64    `xml:"mix,attr"`
+	Stack               string     `xml:"stack,attr"`
+	WCET                float64    `xml:"WCET,attr"`
+	ACET                float64    `xml:"ACET,attr"`
+	ETStddev            float64    `xml:"et_stddev,attr"`
+	PreemptionCost      int        `xml:"preemption_cost,attr"`
+	Fields              []xml.Attr `xml:",any,attr"`
+}
+
+// simsoMs returns a time of the task set in milliseconds of SimSo
+func simsoMs(t int) float64 {
+	return float64(t) / simsoUnitsPerMs
+}
+
+// SimSoScheduler returns the SimSo scheduler of a priority policy: RM and EDF have their own partitioned schedulers,
+// and the other fixed priorities are given to the FP scheduler, since SimSo has no partitioned FP scheduler
+func SimSoScheduler(policy string) string {
+	switch policy {
+	case "RM", "":
+		return SimSoRM
+	case "EDF":
+		return SimSoEDF
+	}
+	return SimSoFP
+}
+
+// WriteSimSo writes the task set as a SimSo configuration file with the given scheduler, or without it, the scheduler
+// of the policy of the platform (see SimSoScheduler). The time unit of the task set is one microsecond, so the times
+// of SimSo, which are in milliseconds, are a thousandth of the times of the task set, and the tasks run for their
+// WCET. SimSo has no release jitter, so the tasks are released periodically from their offset. The core, the BCET (in
+// milliseconds), and the fixed priority of each task are custom fields of the task ("pe", "bcet", and "priority",
+// where a larger value is a higher priority as in SimSo). The partitioned schedulers of SimSo map the tasks to the
+// cores with their own bin packing, so the "pe" field records the mapping of the task set.
+func WriteSimSo(path string, tasks common.TaskSet, platform Platform, scheduler string) error {
+	if platform.Cores < 1 {
+		return fmt.Errorf("unknown number of cores for SimSo")
+	}
+	if scheduler == "" {
+		scheduler = SimSoScheduler(platform.Policy)
+	}
+	if scheduler == SimSoFP && !platform.fixedPriority() {
+		return fmt.Errorf("the FP scheduler of SimSo needs the fixed priorities of the tasks")
+	}
+
+	simulation := simsoSimulation{
+		Duration:    int64(platform.duration(tasks)) * simsoCyclesPerMs / simsoUnitsPerMs,
+		CyclesPerMs: simsoCyclesPerMs,
+		ETM:         "wcet",
+		Sched:       simsoSched{Class: scheduler},
+		Caches:      simsoCaches{MemoryAccessTime: 100},
+	}
+	for c := 0; c < platform.Cores; c++ {
+		simulation.Processors.Processors = append(simulation.Processors.Processors, simsoProcessor{
+			Name:  fmt.Sprintf("CPU %d", c+1),
+			ID:    c + 1,
+			Speed: 1.0,
+		})
+	}
+
+	simulation.Tasks.Fields = []simsoField{{Name: "pe", Type: "int"}, {Name: "bcet", Type: "float"}}
+	if platform.fixedPriority() {
+		simulation.Tasks.Fields = append(simulation.Tasks.Fields, simsoField{Name: "priority", Type: "int"})
+	}
+	// SimSo gives a higher priority to a larger value
+	lowest := 0
+	for _, p := range platform.Priorities {
+		lowest = max(lowest, p)
+	}
+	for i, t := range tasks {
+		task := simsoTask{
+			ID:             i + 1,
+			Name:           fmt.Sprintf("TASK T%d", i+1),
+			TaskType:       "Periodic",
+			AbortOnMiss:    "yes",
+			Period:         simsoMs(t.Period),
+			ActivationDate: simsoMs(t.Offset),
+			Deadline:       simsoMs(t.Deadline),
+			BaseCPI:        1.0,
+			Mix:            0.5,
+			WCET:           simsoMs(t.WCET),
+			ACET:           simsoMs(t.BCET+t.WCET) / 2,
+			Fields: []xml.Attr{
+				{Name: xml.Name{Local: "pe"}, Value: fmt.Sprint(t.PE)},
+				{Name: xml.Name{Local: "bcet"}, Value: fmt.Sprint(simsoMs(t.BCET))},
+			},
+		}
+		if platform.fixedPriority() {
+			task.Fields = append(task.Fields, xml.Attr{Name: xml.Name{Local: "priority"},
+				Value: fmt.Sprint(lowest + 1 - platform.Priorities[i])})
+		}
+		simulation.Tasks.Tasks = append(simulation.Tasks.Tasks, task)
+	}
+
+	data, err := xml.MarshalIndent(simulation, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append([]byte(xml.Header), append(data, '\n')...), 0644)
+}
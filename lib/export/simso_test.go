@@ -0,0 +1,94 @@
+package export
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"task-generator/lib/common"
+	"testing"
+)
+
+// readSimSo reads back a SimSo configuration file
+func readSimSo(t *testing.T, path string) simsoSimulation {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var simulation simsoSimulation
+	if err := xml.Unmarshal(data, &simulation); err != nil {
+		t.Fatal(err)
+	}
+	return simulation
+}
+
+// simsoFields returns the custom fields of a SimSo task by their names
+func simsoFields(task simsoTask) map[string]string {
+	fields := make(map[string]string)
+	for _, attr := range task.Fields {
+		fields[attr.Name.Local] = attr.Value
+	}
+	return fields
+}
+
+func TestWriteSimSo(t *testing.T) {
+	tasks := common.TaskSet{
+		{TaskID: 0, BCET: 1000, WCET: 2500, Period: 10000, Deadline: 8000, PE: 1},
+		{TaskID: 1, BCET: 500, WCET: 1500, Period: 20000, Deadline: 20000, Offset: 3000},
+	}
+	path := filepath.Join(t.TempDir(), "simso.xml")
+	if err := WriteSimSo(path, tasks, Platform{Cores: 2, Policy: "RM", Priorities: []int{1, 2}}, ""); err != nil {
+		t.Fatal(err)
+	}
+	simulation := readSimSo(t, path)
+
+	// the feasibility interval of the set is 2*20000+3000 us, i.e., 43 ms of 1000000 cycles
+	if simulation.Sched.Class != SimSoRM || simulation.Duration != 43000000 ||
+		simulation.CyclesPerMs != simsoCyclesPerMs || len(simulation.Processors.Processors) != 2 {
+		t.Errorf("simulation of %d cycles with %s on %d processors", simulation.Duration, simulation.Sched.Class,
+			len(simulation.Processors.Processors))
+	}
+	if len(simulation.Tasks.Tasks) != 2 {
+		t.Fatalf("%d tasks instead of 2", len(simulation.Tasks.Tasks))
+	}
+	first, second := simulation.Tasks.Tasks[0], simulation.Tasks.Tasks[1]
+	if first.Period != 10 || first.Deadline != 8 || first.WCET != 2.5 || first.ACET != 1.75 ||
+		first.ActivationDate != 0 || second.ActivationDate != 3 {
+		t.Errorf("the times of the tasks are not in milliseconds: %+v, %+v", first, second)
+	}
+	// a larger value is a higher priority in SimSo
+	if fields := simsoFields(first); fields["pe"] != "1" || fields["bcet"] != "1" || fields["priority"] != "2" {
+		t.Errorf("the fields of the first task are %v", fields)
+	}
+	if fields := simsoFields(second); fields["pe"] != "0" || fields["priority"] != "1" {
+		t.Errorf("the fields of the second task are %v", fields)
+	}
+
+	// EDF has no priorities, and the FP scheduler needs them
+	if err := WriteSimSo(path, tasks, Platform{Cores: 2, Policy: "EDF", Duration: 5000}, ""); err != nil {
+		t.Fatal(err)
+	}
+	simulation = readSimSo(t, path)
+	if simulation.Sched.Class != SimSoEDF || simulation.Duration != 5000000 || len(simulation.Tasks.Fields) != 2 {
+		t.Errorf("EDF simulation of %d cycles with %s and the fields %v", simulation.Duration,
+			simulation.Sched.Class, simulation.Tasks.Fields)
+	}
+	if _, ok := simsoFields(simulation.Tasks.Tasks[0])["priority"]; ok {
+		t.Error("the EDF tasks have a priority")
+	}
+	if err := WriteSimSo(path, tasks, Platform{Cores: 2, Policy: "EDF"}, SimSoFP); err == nil {
+		t.Error("the FP scheduler is written without priorities")
+	}
+	if err := WriteSimSo(path, tasks, Platform{Policy: "RM", Priorities: []int{1, 2}}, ""); err == nil {
+		t.Error("the simulation is written without cores")
+	}
+}
+
+func TestSimSoScheduler(t *testing.T) {
+	for policy, want := range map[string]string{"": SimSoRM, "RM": SimSoRM, "EDF": SimSoEDF, "DM": SimSoFP,
+		"OPA": SimSoFP} {
+		if scheduler := SimSoScheduler(policy); scheduler != want {
+			t.Errorf("SimSoScheduler(%q) = %s, want %s", policy, scheduler, want)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package lib
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"task-generator/lib/common"
+)
+
+// findTaskSetPaths A function to find the path of all the task sets in the task set folder
+func findTaskSetPaths(taskSetPath string, outputFormat string) ([]string, error) {
+	// we have to find all the task sets with csv extension in
+	var taskSetPaths []string
+	err := filepath.Walk(taskSetPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		// check folder name to be "tasksets"
+		if filepath.Ext(path) == "."+outputFormat && filepath.Base(filepath.Dir(path)) == "tasksets" {
+			// other files in the folder (DAGs, manifests, ...) carry an extra suffix, e.g., "uniform_0.prec.csv"
+			stem := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+			if !strings.Contains(stem, ".") {
+				taskSetPaths = append(taskSetPaths, path)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot find any task set in the folder %s: %v", taskSetPath, err)
+	}
+	return taskSetPaths, nil
+}
+
+// readTaskSet reads a task set in the given format
+func readTaskSet(path string, outputFormat string) (common.TaskSet, error) {
+	switch outputFormat {
+	case "csv":
+		return common.ReadTaskSet(path)
+	case "json":
+		return common.ReadTaskSetJSON(path)
+	}
+	return common.ReadTaskSetYAML(path)
+}
+
+// writeTaskSet writes a task set in the given format
+func writeTaskSet(tasks common.TaskSet, path string, outputFormat string) error {
+	switch outputFormat {
+	case "csv":
+		return tasks.WriteTaskSet(path)
+	case "json":
+		return tasks.WriteTaskSetJSON(path)
+	}
+	return tasks.WriteTaskSetYAML(path)
+}
+
+// readVertexSet reads a vertex set in the given format
+func readVertexSet(path string, outputFormat string) (common.VertexSet, error) {
+	switch outputFormat {
+	case "csv":
+		return common.ReadVertexSet(path)
+	case "json":
+		return common.ReadVertexSetJSON(path)
+	}
+	return common.ReadVertexSetYAML(path)
+}
+
+// writeVertexSet writes a vertex set in the given format
+func writeVertexSet(vertices common.VertexSet, path string, outputFormat string) error {
+	switch outputFormat {
+	case "csv":
+		return vertices.WriteVertexSet(path)
+	case "json":
+		return vertices.WriteVertexSetJSON(path)
+	}
+	return vertices.WriteVertexSetYAML(path)
+}
+
+// readJobSet reads a job set in the given format, with the dependencies of the ".prec" file of a CSV job set
+func readJobSet(path string, outputFormat string) (common.JobSet, error) {
+	switch outputFormat {
+	case "csv":
+		jobs, err := common.ReadJobSet(path)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := os.Stat(precPath(path, outputFormat)); err == nil {
+			if err := jobs.ReadDependencyJobSet(precPath(path, outputFormat)); err != nil {
+				return nil, err
+			}
+		}
+		return jobs, nil
+	case "json":
+		return common.ReadJobSetJSON(path)
+	}
+	return common.ReadJobSetYAML(path)
+}
+
+// precPath returns the path of the precedence graph of a task set or job set, e.g., "uniform_0.prec.csv"
+func precPath(path string, outputFormat string) string {
+	return path[:strings.LastIndex(path, ".")] + ".prec." + outputFormat
+}
+
+// dotPath returns the path of the dot file of a task set
+func dotPath(taskSetPath string) string {
+	return taskSetPath[:strings.LastIndex(taskSetPath, ".")] + ".dot"
+}
+
+// jobSetPath returns the path of the job set of a task set,
+// i.e., "<...>/jobsets/jobset-uniform_0.csv" for "<...>/tasksets/uniform_0.csv"
+func jobSetPath(taskSetPath string) string {
+	// remove folders before file name
+	fileName := filepath.Base(taskSetPath)
+	// get the taskPath without the file name and remove tasksets from it
+	mainPath := filepath.Dir(filepath.Dir(taskSetPath))
+	// add jobsets folder and jobset before the file name
+	return filepath.Join(mainPath, "jobsets", "jobset-"+fileName)
+}
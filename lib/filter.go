@@ -0,0 +1,145 @@
+package lib
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"task-generator/lib/analysis"
+	"task-generator/lib/common"
+)
+
+// acceptanceFile is the name of the report of the acceptance ratio of the filter of each point, in the root folder
+const acceptanceFile = "acceptance.csv"
+
+// errFilterExhausted is returned when no task set passes the filter within the maximum number of attempts
+var errFilterExhausted = errors.New("no task set passes the filter")
+
+// filter keeps the task sets with the given verdict of a schedulability test
+type filter struct {
+	test        string
+	policy      string
+	schedulable bool
+	maxAttempts int
+}
+
+// checkFilter checks the filter of the task sets
+func checkFilter(f *filter) error {
+	if f == nil {
+		return nil
+	}
+	if !slices.Contains(AnalysisTests, f.test) || slices.Contains(dagTests, f.test) {
+		return fmt.Errorf("invalid schedulability test of the filter: %s", f.test)
+	}
+	if f.test == "rta" || f.test == "bc-rta" {
+		// the generated task sets have no explicit priorities
+		if !slices.Contains(analysis.PriorityPolicies, f.policy) || f.policy == analysis.Explicit {
+			return fmt.Errorf("invalid priority policy of the filter: %s", f.policy)
+		}
+		if f.test == "bc-rta" && f.policy == analysis.OPA {
+			return fmt.Errorf("the %s priorities of the filter are only defined for the partitioned rta", f.policy)
+		}
+	}
+	if f.maxAttempts < 0 {
+		return fmt.Errorf("the maximum number of attempts of the filter should not be negative")
+	}
+	return nil
+}
+
+// accepts reports whether a task set passes the filter
+func (f *filter) accepts(tasks common.TaskSet, cores int) (bool, error) {
+	result, err := runTest(tasks, f.test, f.policy, cores)
+	if err != nil {
+		return false, err
+	}
+	return result.Schedulable == f.schedulable, nil
+}
+
+// keep returns the verdict that the filter keeps
+func (f *filter) keep() string {
+	if f.schedulable {
+		return "schedulable"
+	}
+	return "unschedulable"
+}
+
+// writeAcceptance updates the row of the point of the generator in the acceptance report of the root folder.
+// The rejected task sets of the written sets are in their manifests, and the exhausted sets are the ones for which
+// no task set passed the filter.
+func (g *Generator) writeAcceptance(root string, taskSetPaths []string, exhausted []bool) error {
+	sets, numExhausted, candidates := 0, 0, 0
+	for i, path := range taskSetPaths {
+		if exhausted[i] {
+			numExhausted++
+			candidates += g.filter.maxAttempts
+			continue
+		}
+		manifest, err := readManifest(manifestPath(path))
+		if err != nil {
+			// the set could not be generated
+			continue
+		}
+		sets++
+		candidates += manifest.Rejected + 1
+	}
+	ratio := 0.0
+	if candidates > 0 {
+		ratio = float64(sets) / float64(candidates)
+	}
+	row := []string{
+		g.Dir(),
+		strconv.FormatFloat(g.utilization, 'f', 6, 64),
+		strconv.Itoa(g.numCores),
+		strconv.Itoa(g.numTasks),
+		g.filter.test,
+		g.filter.keep(),
+		strconv.Itoa(sets),
+		strconv.Itoa(numExhausted),
+		strconv.Itoa(candidates),
+		strconv.FormatFloat(ratio, 'f', 6, 64),
+	}
+
+	// the other points of the run keep their rows
+	path := filepath.Join(root, acceptanceFile)
+	var rows [][]string
+	if file, err := os.Open(path); err == nil {
+		rows, err = csv.NewReader(file).ReadAll()
+		file.Close()
+		if err != nil {
+			return fmt.Errorf("cannot read %s: %v", path, err)
+		}
+		if len(rows) > 0 {
+			rows = rows[1:]
+		}
+	}
+	rows = slices.DeleteFunc(rows, func(r []string) bool {
+		return len(r) > 0 && r[0] == row[0]
+	})
+	rows = append(rows, row)
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i][0] < rows[j][0]
+	})
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	headers := []string{"Dir", "Utilization", "Cores", "Tasks", "Test", "Keep", "Sets", "Exhausted", "Candidates",
+		"Acceptance ratio"}
+	writer.Write(headers)
+	for _, r := range rows {
+		if err := writer.Write(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
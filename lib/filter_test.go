@@ -0,0 +1,131 @@
+package lib
+
+import (
+	"encoding/csv"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"task-generator/lib/analysis"
+	"testing"
+)
+
+func TestCheckFilter(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter *filter
+		valid  bool
+	}{
+		{"no filter", nil, true},
+		{"rta", &filter{test: "rta", policy: analysis.RM}, true},
+		{"qpa", &filter{test: "qpa"}, true},
+		{"global test", &filter{test: "gfb", maxAttempts: 10}, true},
+		{"unknown test", &filter{test: "edf"}, false},
+		{"DAG test", &filter{test: "graham"}, false},
+		{"no priorities", &filter{test: "rta"}, false},
+		{"explicit priorities", &filter{test: "rta", policy: analysis.Explicit}, false},
+		{"negative attempts", &filter{test: "qpa", maxAttempts: -1}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := checkFilter(test.filter); (err == nil) != test.valid {
+				t.Errorf("checkFilter() = %v, valid: %v", err, test.valid)
+			}
+		})
+	}
+}
+
+// readAcceptance returns the rows of the acceptance report of a root folder by their folders
+func readAcceptance(t *testing.T, root string) map[string][]string {
+	t.Helper()
+	files := readFiles(t, root)
+	records, err := csv.NewReader(strings.NewReader(files[acceptanceFile])).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := make(map[string][]string)
+	for _, record := range records[1:] {
+		rows[record[0]] = record
+	}
+	return rows
+}
+
+func TestFilter(t *testing.T) {
+	root := t.TempDir()
+	numSets := 6
+	for _, schedulable := range []bool{true, false} {
+		utilization := 0.85
+		if !schedulable {
+			utilization = 0.95
+		}
+		g, err := NewGenerator(WithSeed(3), WithTasks(5), WithUtilization(utilization),
+			WithPeriodDistribution("uniform-discrete", []int{10, 100}, []int{10, 15, 20, 25, 30, 40, 50, 75, 100}),
+			WithFilter("rta", analysis.RM, schedulable, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := g.WriteTaskSets(root, numSets); err != nil {
+			t.Fatal(err)
+		}
+
+		// all the written sets have the verdict of the filter, and their rejected candidates are in their manifests
+		candidates := 0
+		for i := 0; i < numSets; i++ {
+			path := filepath.Join(root, filepath.FromSlash(g.Dir()), "tasksets",
+				fmt.Sprintf("uniform-discrete_%d.csv", i))
+			tasks, err := readTaskSet(path, "csv")
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := runTest(tasks, "rta", analysis.RM, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Schedulable != schedulable {
+				t.Errorf("set %d is schedulable: %v", i, result.Schedulable)
+			}
+			manifest, err := readManifest(manifestPath(path))
+			if err != nil {
+				t.Fatal(err)
+			}
+			candidates += manifest.Rejected + 1
+		}
+
+		row := readAcceptance(t, root)[g.Dir()]
+		want := []string{g.Dir(), strconv.FormatFloat(utilization, 'f', 6, 64), "1", "5", "rta", g.filter.keep(),
+			strconv.Itoa(numSets), "0", strconv.Itoa(candidates),
+			strconv.FormatFloat(float64(numSets)/float64(candidates), 'f', 6, 64)}
+		if strings.Join(row, ",") != strings.Join(want, ",") {
+			t.Errorf("the acceptance of the point is %v instead of %v", row, want)
+		}
+	}
+	// the rows of both points are kept
+	if rows := readAcceptance(t, root); len(rows) != 2 {
+		t.Errorf("%d rows in the acceptance report instead of 2", len(rows))
+	}
+}
+
+func TestFilterExhausted(t *testing.T) {
+	// the sets of a low utilization are always schedulable, so none is kept
+	g, err := NewGenerator(WithSeed(3), WithTasks(4), WithUtilization(0.2),
+		WithPeriodDistribution("uniform-discrete", []int{10, 100}, []int{10, 20, 50, 100}),
+		WithFilter("rta", analysis.DM, false, 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := t.TempDir()
+	if err := g.WriteTaskSets(root, 2); err != nil {
+		t.Fatal(err)
+	}
+	for path := range readFiles(t, root) {
+		if strings.HasSuffix(path, ".csv") && !strings.HasPrefix(filepath.Base(path), "index") &&
+			path != acceptanceFile {
+			t.Errorf("%s is written", path)
+		}
+	}
+	row := readAcceptance(t, root)[g.Dir()]
+	if sets, exhausted, candidates, ratio := row[6], row[7], row[8], row[9]; sets != "0" || exhausted != "2" ||
+		candidates != "6" || ratio != "0.000000" {
+		t.Errorf("the acceptance of the point is %v", row)
+	}
+}
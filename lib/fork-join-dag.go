@@ -1,15 +1,9 @@
 package lib
 
 import (
-	"encoding/csv"
-	"fmt"
 	"math"
 	"math/rand"
-	"os"
-	"path/filepath"
 	"strconv"
-	"strings"
-	"sync"
 	"task-generator/lib/common"
 )
 
@@ -17,9 +11,9 @@ import (
 //	Parallel Tasks Without Preemptions", (RTSS 2018), 2018.
 //	https://retis.sssup.it/~d.casini/resources/DAG_Generator/cptasks.zip
 
-func expandDAG(vertices common.VertexSet, source, sink, depth, numBranches, maxParBranches,
+func expandDAG(rng *rand.Rand, vertices common.VertexSet, source, sink, depth, numBranches, maxParBranches,
 	maxVertices int, pPar float64) common.VertexSet {
-	parBranches := rand.Intn(maxParBranches-1) + 2
+	parBranches := rng.Intn(maxParBranches-1) + 2
 
 	if source == 0 && sink == 0 {
 		// add the source and sink vertices
@@ -28,13 +22,13 @@ func expandDAG(vertices common.VertexSet, source, sink, depth, numBranches, maxP
 
 		vertices = append(vertices, so, si)
 
-		vertices = expandDAG(vertices, 0, 1, depth-1, parBranches, maxParBranches, maxVertices, pPar)
+		vertices = expandDAG(rng, vertices, 0, 1, depth-1, parBranches, maxParBranches, maxVertices, pPar)
 	} else {
 		for i := 0; i < numBranches; i++ {
 			current := len(vertices)
 			vertices = append(vertices, &common.Vertex{VertexID: current})
 
-			r := rand.Float64()
+			r := rng.Float64()
 			isParallelNode := depth > 0 && r < pPar && len(vertices) < maxVertices
 
 			if !isParallelNode {
@@ -56,17 +50,17 @@ func expandDAG(vertices common.VertexSet, source, sink, depth, numBranches, maxP
 				vertices[current].Depth = depth
 				vertices[current+1].Depth = -depth
 
-				vertices = expandDAG(vertices, current, current+1, depth-1, parBranches, maxParBranches, maxVertices, pPar)
+				vertices = expandDAG(rng, vertices, current, current+1, depth-1, parBranches, maxParBranches, maxVertices, pPar)
 			}
 		}
 	}
 	return vertices
 }
 
-func addRandomEdgesToDAG(vertices common.VertexSet, pAdd float64) common.VertexSet {
+func addRandomEdgesToDAG(rng *rand.Rand, vertices common.VertexSet, pAdd float64) common.VertexSet {
 	for i := range vertices {
 		for j := range vertices {
-			r := rand.Float64()
+			r := rng.Float64()
 
 			if vertices[i].Depth > vertices[j].Depth && !contains(vertices[i].Successors, j) && r < pAdd {
 				vertices[i].Successors = append(vertices[i].Successors, j)
@@ -87,11 +81,11 @@ func contains(slice []int, val int) bool {
 }
 
 // Generates n random integers that sum to s
-func generateRandomSum(n, s int) []int {
+func generateRandomSum(rng *rand.Rand, n, s int) []int {
 	// n random floats
 	randN := make([]float64, n)
 	for i := range randN {
-		randN[i] = rand.Float64()
+		randN[i] = rng.Float64()
 	}
 
 	// extend the floats so the sum is approximately x (might be up to 3 less, because of flooring)
@@ -106,7 +100,7 @@ func generateRandomSum(n, s int) []int {
 	}
 
 	for i := 0; i < s-sum(result); i++ {
-		idx := rand.Intn(n)
+		idx := rng.Intn(n)
 		result[idx]++
 	}
 
@@ -121,14 +115,14 @@ func sum(slice []int) int {
 	return sum
 }
 
-func generateBCET(totalBCET, totalWCET int, wcetList []int) []int {
+func generateBCET(rng *rand.Rand, totalBCET, totalWCET int, wcetList []int) []int {
 	bcetList := make([]int, len(wcetList))
 	for i := range wcetList {
 		bcetList[i] = int(math.Round(float64(wcetList[i]) * float64(totalBCET) / float64(totalWCET)))
 	}
 
 	for sum(bcetList) < totalBCET {
-		idx := rand.Intn(len(bcetList))
+		idx := rng.Intn(len(bcetList))
 
 		if bcetList[idx] < wcetList[idx] {
 			bcetList[idx]++
@@ -137,222 +131,88 @@ func generateBCET(totalBCET, totalWCET int, wcetList []int) []int {
 
 	return bcetList
 }
-func generateDAGFromTask(task common.Task, pPar, pAdd float64, maxParBranches, maxVertices, maxDepth int) common.VertexSet {
+func generateDAGFromTask(rng *rand.Rand, task common.Task, pPar, pAdd float64, maxParBranches, maxVertices, maxDepth int) common.VertexSet {
 
 	vertices := common.VertexSet{}
-	vertices = expandDAG(vertices, 0, 0, maxDepth, 1, maxParBranches, maxVertices, pPar)
-	vertices = addRandomEdgesToDAG(vertices, pAdd)
-	wcetList := generateRandomSum(len(vertices), task.WCET)
-	bcetList := generateBCET(task.BCET, task.WCET, wcetList)
+	vertices = expandDAG(rng, vertices, 0, 0, maxDepth, 1, maxParBranches, maxVertices, pPar)
+	vertices = addRandomEdgesToDAG(rng, vertices, pAdd)
+	wcetList := generateRandomSum(rng, len(vertices), task.WCET)
+	bcetList := generateBCET(rng, task.BCET, task.WCET, wcetList)
+
+	// the budget of each criticality level is split like the WCET
+	var levelBudgets [][]int
+	for level, budget := range task.WCETs {
+		if level == common.LO {
+			levelBudgets = append(levelBudgets, wcetList)
+		} else {
+			levelBudgets = append(levelBudgets, splitBudget(wcetList, task.WCET, budget))
+		}
+	}
 
 	for i := range vertices {
 		vertices[i].TaskID = task.TaskID
 		vertices[i].Jitter = task.Jitter
 		vertices[i].BCET = bcetList[i]
 		vertices[i].WCET = wcetList[i]
+		vertices[i].Criticality = task.Criticality
+		for _, budgets := range levelBudgets {
+			vertices[i].WCETs = append(vertices[i].WCETs, budgets[i])
+		}
 	}
 	return vertices
 }
 
-func generateDAGSet(taskPath string, pPar, pAdd float64, maxParBranches, maxVertices, maxDepth int,
-	makeDotFile bool, outputFormat string) {
-	var taskSet common.TaskSet
-	var err error
-	// first we have to read the task set
-	if outputFormat == "csv" {
-		taskSet, err = common.ReadTaskSet(taskPath)
-		if err != nil {
-			logger.LogFatal("Error reading task set: " + err.Error())
-		}
-	} else {
-		taskSet, err = common.ReadTaskSetYAML(taskPath)
-		if err != nil {
-			logger.LogFatal("Error reading task set: " + err.Error())
-		}
-
-	}
-
-	// add ".prec" at the end of file before its format and write the set of vertices to a file
-	mainPath := taskPath[:strings.LastIndex(taskPath, ".")] + ".prec." + outputFormat
-
-	// create the whole path
-	err = os.MkdirAll(filepath.Dir(mainPath), os.ModePerm)
-	file, err := os.Create(mainPath)
-	if err != nil {
-		logger.LogFatal("Error creating file: " + err.Error())
-	}
-
-	dotFile := ""
-
-	if outputFormat == "csv" {
-		// write the job set to a file
-		writer := csv.NewWriter(file)
-		writer.Write([]string{"Task ID", "Vertex ID", "Jitter", "BCET", "WCET", "Period", "Deadline", "Successors"})
-
-		defer writer.Flush()
-
-		vertexIDCounter := 0
-		for _, task := range taskSet {
-			newDAG := generateDAGFromTask(*task, pPar, pAdd, maxParBranches, maxVertices, maxDepth)
-			// first we have to write the task
-			if makeDotFile {
-				dotFile += newDAG.GenerateDotFile("T"+string(task.TaskID), vertexIDCounter)
-			}
-			for _, vertex := range newDAG {
-				lineTemp := []string{strconv.Itoa(vertex.TaskID), strconv.Itoa(vertex.VertexID + vertexIDCounter),
-					strconv.Itoa(vertex.Jitter), strconv.Itoa(vertex.BCET), strconv.Itoa(vertex.WCET),
-					strconv.Itoa(task.Period), strconv.Itoa(task.Deadline)}
-
-				succStr := "["
-				for _, succ := range vertex.Successors {
-					succStr += strconv.Itoa(succ+vertexIDCounter) + ","
-				}
-				if len(succStr) > 1 {
-					succStr = succStr[:len(succStr)-1]
-				}
-				succStr += "]"
-				lineTemp = append(lineTemp, succStr)
-				if err := writer.Write(lineTemp); err != nil {
-					logger.LogFatal("Error writing to file: " + err.Error())
-				}
-
-			}
-			vertexIDCounter += len(newDAG)
-		}
-	} else {
-		// we need to add vertexset as the root element
-		_, err = file.WriteString("vertexset:\n")
-		if err != nil {
-			logger.LogFatal("Error writing to file: " + err.Error())
-		}
-		vertexIDCounter := 0
-		for _, task := range taskSet {
-			newDAG := generateDAGFromTask(*task, pPar, pAdd, maxParBranches, maxVertices, maxDepth)
-			// first we have to write the task
-			if makeDotFile {
-				dotFile += newDAG.GenerateDotFile("T"+string(task.TaskID), vertexIDCounter)
-			}
-			for _, vertex := range newDAG {
-				// write the vertex set to the file with yaml format
-				_, err = file.WriteString(fmt.Sprintf("  - TaskID: %d\n", vertex.TaskID))
-				_, err = file.WriteString(fmt.Sprintf("    VertexID: %d\n", vertex.VertexID+vertexIDCounter))
-				_, err = file.WriteString(fmt.Sprintf("    Jitter: %d\n", vertex.Jitter))
-				_, err = file.WriteString(fmt.Sprintf("    BCET: %d\n", vertex.BCET))
-				_, err = file.WriteString(fmt.Sprintf("    WCET: %d\n", vertex.WCET))
-				_, err = file.WriteString(fmt.Sprintf("    Period: %d\n", task.Period))
-				_, err = file.WriteString(fmt.Sprintf("    Deadline: %d\n", task.Deadline))
-				_, err = file.WriteString(fmt.Sprintf("    PE: %d\n", task.PE))
-
-				succStr := "["
-				for _, succ := range vertex.Successors {
-					succStr += strconv.Itoa(succ+vertexIDCounter) + ","
-				}
-				if len(succStr) > 1 {
-					succStr = succStr[:len(succStr)-1]
-				}
-				succStr += "]"
-				_, err = file.WriteString(fmt.Sprintf("    Successors: %s\n", succStr))
-
-				if err != nil {
-					logger.LogFatal("Error writing to file: " + err.Error())
-				}
-			}
-			vertexIDCounter += len(newDAG)
-		}
-
-	}
-
-	// write the dot file
-	if makeDotFile {
-		dotPath := taskPath[:strings.LastIndex(taskPath, ".")] + ".dot"
-		os.MkdirAll(filepath.Dir(dotPath), os.ModePerm)
-		// write the dot file
-		writerDot, err := os.Create(dotPath)
-		if err != nil {
-			logger.LogFatal("Error creating file: " + err.Error())
-		}
-
-		dotFile = "digraph G {\n" + dotFile + "}\n"
-
-		if _, err := writerDot.WriteString(dotFile); err != nil {
-			logger.LogFatal("Error writing to file: " + err.Error())
-		}
-		// close the file
-		writerDot.Close()
+// splitBudget splits the budget of a criticality level among the vertices in proportion to their WCETs, where
+// totalWCET is the WCET of the task
+func splitBudget(wcetList []int, totalWCET, budget int) []int {
+	budgets := make([]int, len(wcetList))
+	for i, wcet := range wcetList {
+		budgets[i] = int(math.Floor(float64(wcet) * float64(budget) / float64(totalWCET)))
 	}
-
+	return budgets
 }
 
-// findTaskSetPaths A function to find the path of all the task sets in the task set folder
-func findTaskSetPaths(taskSetPath string, outputFormat string) []string {
-	// we have to find all the task sets with csv extension in
-	var taskSetPaths []string
-	err := filepath.Walk(taskSetPath, func(path string, info os.FileInfo, err error) error {
-		// check folder name to be "tasksets"
-		if filepath.Ext(path) == "."+outputFormat && filepath.Base(filepath.Dir(path)) == "tasksets" {
-			if strings.LastIndex(path, ".prec."+outputFormat) == -1 {
-				taskSetPaths = append(taskSetPaths, path)
+// generateForkJoinDAGs generates a fork-join DAG for each task of the task set. The vertices of all DAGs are numbered
+// one after the other, so each vertex has a unique ID in the returned set.
+func generateForkJoinDAGs(taskSet common.TaskSet, seed int64, pPar, pAdd float64, maxParBranches, maxVertices,
+	maxDepth int) common.VertexSet {
+	var vertices common.VertexSet
+	for _, task := range taskSet {
+		// each task gets its own random stream, so its DAG does not depend on the other tasks
+		rng := newRand(seed, streamDAG, strconv.Itoa(task.TaskID))
+		newDAG := generateDAGFromTask(rng, *task, pPar, pAdd, maxParBranches, maxVertices, maxDepth)
+
+		// now we shift the IDs of the DAG after the vertices of the previous tasks
+		offset := len(vertices)
+		for _, vertex := range newDAG {
+			vertex.VertexID += offset
+			for i := range vertex.Successors {
+				vertex.Successors[i] += offset
+			}
+			for i := range vertex.Predecessors {
+				vertex.Predecessors[i] += offset
 			}
+			vertex.Period = task.Period
+			vertex.Deadline = task.Deadline
+			vertex.PE = task.PE
+			vertex.Offset = task.Offset
 		}
-		return nil
-	})
-
-	if err != nil {
-		logger.LogFatal("Cannot find any task set in the folder: " + taskSetPath)
-	} else {
-		// print the number of task sets
-		logger.LogInfo("Number of founded task sets: " + strconv.Itoa(len(taskSetPaths)))
-	}
-
-	if err != nil {
-		logger.LogFatal("Cannot find any task set in the folder: " + taskSetPath)
+		vertices = append(vertices, newDAG...)
 	}
-
-	return taskSetPaths
+	return vertices
 }
 
-// GenerateDAGSets generates DAG sets for each task set in the task set folder
-func GenerateDAGSets(taskSetPath string, pPar, pAdd float64, maxParBranches, maxVertices, maxDepth int,
-	makeDotFile bool, outputFormat string) {
-	// first we have to find all the task sets with csv extension in
-	taskSetPaths := findTaskSetPaths(taskSetPath, outputFormat)
-
-	// now we have to generate the job sets
-	for _, taskSetPath := range taskSetPaths {
-		// make sure that the file does not exist
-		predPath := taskSetPath[:strings.LastIndex(taskSetPath, ".")] + ".prec." + outputFormat
-		if _, err := os.Stat(predPath); os.IsNotExist(err) {
-			logger.LogInfo("Generating DAG for: " + taskSetPath)
-			generateDAGSet(taskSetPath, pPar, pAdd, maxParBranches, maxVertices, maxDepth, makeDotFile, outputFormat)
-		} else {
-			logger.LogInfo(fmt.Sprintf("%s exists", predPath))
+// forkJoinDotFile makes a dot file with one cluster for the DAG of each task
+func forkJoinDotFile(vertices common.VertexSet) string {
+	dotFile := ""
+	for start := 0; start < len(vertices); {
+		end := start
+		for end < len(vertices) && vertices[end].TaskID == vertices[start].TaskID {
+			end++
 		}
+		dag := vertices[start:end]
+		dotFile += dag.GenerateDotFile("T"+strconv.Itoa(vertices[start].TaskID), 0)
+		start = end
 	}
-}
-
-// GenerateDAGSetsParallel generates DAG sets for each task set in the task set folder in parallel
-func GenerateDAGSetsParallel(taskSetPath string, pPar, pAdd float64, maxParBranches, maxVertices, maxDepth int,
-	makeDotFile bool, outputFormat string) {
-	// first we have to find all the task sets with csv extension in
-	taskSetPaths := findTaskSetPaths(taskSetPath, outputFormat)
-
-	// now we have to generate the job sets in parallel
-	var wg sync.WaitGroup
-	wg.Add(len(taskSetPaths))
-	for i := 0; i < len(taskSetPaths); i++ {
-		go func(setIndex int) {
-			defer wg.Done()
-			// make sure that the file does not exist
-
-			predPath := taskSetPaths[setIndex][:strings.LastIndex(taskSetPaths[setIndex], ".")] + ".prec." + outputFormat
-			if _, err := os.Stat(predPath); os.IsNotExist(err) {
-				logger.LogInfo("Generating DAG for: " + taskSetPaths[setIndex])
-				generateDAGSet(taskSetPaths[setIndex], pPar, pAdd, maxParBranches, maxVertices, maxDepth,
-					makeDotFile, outputFormat)
-			} else {
-				logger.LogInfo(fmt.Sprintf("%s exists", predPath))
-			}
-		}(i)
-	}
-	wg.Wait()
+	return "digraph G {\n" + dotFile + "}\n"
 }
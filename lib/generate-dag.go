@@ -0,0 +1,103 @@
+package lib
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"sync"
+	"task-generator/lib/common"
+)
+
+// generateVertexSet generates the DAG of a task set with the seed of its DAG stream
+func (g *Generator) generateVertexSet(taskSet common.TaskSet, seed int64) (common.VertexSet, error) {
+	switch g.dagType {
+	case "fork-join":
+		return generateForkJoinDAGs(taskSet, seed, g.forkProb, g.edgeProb, g.maxBranches, g.maxVertices, g.maxDepth), nil
+	case "random":
+		return generateDAG(rand.New(rand.NewSource(seed)), taskSet, g.numRoots, g.maxBranches, g.maxDepth)
+	case "chain":
+		return generateTaskChain(rand.New(rand.NewSource(seed)), taskSet), nil
+	}
+	return nil, fmt.Errorf("no DAG type is set")
+}
+
+// DAG generates the DAG of the task set with the given index in memory.
+// It is the same DAG that WriteDAGSets writes for this task set.
+func (g *Generator) DAG(taskSet common.TaskSet, index int) (common.VertexSet, error) {
+	return g.generateVertexSet(taskSet, deriveSeed(g.seed, streamDAG, g.setKey(index)))
+}
+
+// dotFile makes the dot file of a vertex set
+func (g *Generator) dotFile(vertices common.VertexSet) string {
+	if g.dagType == "fork-join" {
+		return forkJoinDotFile(vertices)
+	}
+	return "digraph G {\n" + vertices.GenerateDotFile("DAG", 0) + "\n}"
+}
+
+// writeDAGSet generates the DAG of a task set file and writes it next to the task set
+func (g *Generator) writeDAGSet(taskSetPath string, seed int64, makeDotFile bool) error {
+	taskSet, err := readTaskSet(taskSetPath, g.outputFormat)
+	if err != nil {
+		return fmt.Errorf("error reading task set: %w", err)
+	}
+	vertices, err := g.generateVertexSet(taskSet, seed)
+	if err != nil {
+		return err
+	}
+
+	// write the set of vertices to the ".prec" file
+	if err := writeVertexSet(vertices, precPath(taskSetPath, g.outputFormat), g.outputFormat); err != nil {
+		return err
+	}
+
+	// write the dot file
+	if makeDotFile {
+		return os.WriteFile(dotPath(taskSetPath), []byte(g.dotFile(vertices)), 0644)
+	}
+	return nil
+}
+
+// WriteDAGSets generates the DAG of each task set in the root folder and writes it next to the task set.
+// The DAGs that already exist are kept.
+func (g *Generator) WriteDAGSets(root string, makeDotFile bool) error {
+	taskSetPaths, err := findTaskSetPaths(root, g.outputFormat)
+	if err != nil {
+		return err
+	}
+	g.logger.LogInfo(fmt.Sprintf("Number of found task sets: %d", len(taskSetPaths)))
+
+	errs := make([]error, len(taskSetPaths))
+	create := func(i int) {
+		path := taskSetPaths[i]
+		// make sure that the file does not exist
+		predPath := precPath(path, g.outputFormat)
+		if _, err := os.Stat(predPath); os.IsNotExist(err) {
+			g.logger.LogInfo("Generating DAG for: " + path)
+			seed := deriveSeed(g.seed, streamDAG, seedKey(root, path))
+			if err := g.writeDAGSet(path, seed, makeDotFile); err != nil {
+				errs[i] = fmt.Errorf("%s: %w", path, err)
+			}
+		} else {
+			g.logger.LogInfo(fmt.Sprintf("%s exists", predPath))
+		}
+	}
+
+	if g.parallel {
+		var wg sync.WaitGroup
+		wg.Add(len(taskSetPaths))
+		for i := range taskSetPaths {
+			go func(setIndex int) {
+				defer wg.Done()
+				create(setIndex)
+			}(i)
+		}
+		wg.Wait()
+	} else {
+		for i := range taskSetPaths {
+			create(i)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -1,11 +1,14 @@
 package lib
 
 import (
+	"errors"
+	"fmt"
+	"math/rand"
 	"os"
 	"path/filepath"
 	"strconv"
-	"strings"
 	"sync"
+	"task-generator/lib/analysis"
 	"task-generator/lib/common"
 )
 
@@ -16,273 +19,287 @@ const (
 	DM = 1
 	// EDF is Earliest Deadline First
 	EDF = 2
+	// DkC is D - C Monotonic
+	DkC = 3
+	// OPA is Audsley's Optimal Priority Assignment with the response-time analysis of each core
+	OPA = 4
+	// Explicit takes the priorities of the task sets
+	Explicit = 5
 )
 
-// generateJobSets generates jobs of each task set in one hyperperiod
-func generateJobSet(taskPath string, priorityAssignment int, outputFormat string) {
-	// first let's see we have a prec file or not
-	precPath := taskPath[:strings.LastIndex(taskPath, ".")] + ".prec." + outputFormat
-	if _, err := os.Stat(precPath); err == nil {
-		// read the precedence graph
-		var precGraph common.VertexSet
-		var err error
-
-		// first we have to read the task set
-		if outputFormat == "csv" {
-			precGraph, err = common.ReadVertexSet(precPath)
-		} else {
-			precGraph, err = common.ReadVertexSetYAML(precPath)
-		}
-
-		if err != nil {
-			logger.LogFatal("Error reading precedence graph: " + err.Error())
-		}
+// fixedPriorityPolicies are the names of the fixed-priority assignments in the analysis
+var fixedPriorityPolicies = map[int]string{
+	RM:       analysis.RM,
+	DM:       analysis.DM,
+	DkC:      analysis.DkC,
+	OPA:      analysis.OPA,
+	Explicit: analysis.Explicit,
+}
 
-		// print the number of vertices
-		logger.LogInfo("Number of vertices in the precedence graph: " + strconv.Itoa(len(precGraph)))
-		// print the vertices
-		for i, vertex := range precGraph {
-			logger.LogDebug("Vertex: " + strconv.Itoa(i) + " " + strconv.Itoa(vertex.VertexID) + " " + strconv.Itoa(vertex.Jitter) + " " + strconv.Itoa(vertex.BCET) + " " + strconv.Itoa(vertex.WCET) + " ")
-		}
+// taskPriorities returns the fixed priority of each task by its ID under the priority assignment of the generator, or
+// nil for EDF
+func (g *Generator) taskPriorities(tasks common.TaskSet) (map[int]int, error) {
+	if g.priorityAssignment == EDF {
+		return nil, nil
+	}
+	priorities, err := analysis.Priorities(tasks, fixedPriorityPolicies[g.priorityAssignment])
+	if err != nil {
+		return nil, err
+	}
+	byTask := make(map[int]int, len(tasks))
+	for i, task := range tasks {
+		byTask[task.TaskID] = priorities[i]
+	}
+	return byTask, nil
+}
 
-		// calculate the hyperperiod
-		hyperperiod := precGraph.HyperPeriod()
-		if hyperperiod == -1 {
-			logger.LogFatal("Error calculating hyperperiod")
-		}
+// jobPriority returns the priority of a job, which is the fixed priority of its task, or its absolute deadline for EDF
+func jobPriority(priorities map[int]int, taskID, absoluteDeadline int) int {
+	if priorities == nil {
+		return absoluteDeadline
+	}
+	return priorities[taskID]
+}
 
-		// now first let's create the job set
-		jobSet := common.JobSet{}
-		uniqueID := 0
-		for _, vertex := range precGraph {
-			// first we have to calculate the number of jobs
-			numJobs := hyperperiod / vertex.Period
-			for j := 0; j < numJobs; j++ {
-				// now we have to calculate the arrival time
-				earliestArrivalTime := j * vertex.Period
-				latestArrivalTime := earliestArrivalTime + vertex.Jitter
-				// now we have to calculate the deadline
-				deadline := earliestArrivalTime + vertex.Deadline
-				// now we have to calculate the priority
-				priority := 0
-				switch priorityAssignment {
-				case RM:
-					priority = vertex.Period
-				case DM:
-					priority = vertex.Deadline
-				case EDF:
-					priority = deadline
-				}
-				// now we have to create the job
-				jobSet = append(jobSet, &common.Job{
-					Vertex:              vertex,
-					TaskID:              vertex.VertexID,
-					JobID:               uniqueID,
-					EarliestArrivalTime: earliestArrivalTime,
-					LatestArrivalTime:   latestArrivalTime,
-					AbsoluteDeadline:    deadline,
-					Priority:            priority,
-				})
+// logJob prints a job in debug mode
+func (g *Generator) logJob(job *common.Job, bcet, wcet int) {
+	g.logger.LogDebug("Job: " + strconv.Itoa(job.TaskID) + " " +
+		strconv.Itoa(job.JobID) + " " +
+		strconv.Itoa(job.EarliestArrivalTime) + " " +
+		strconv.Itoa(job.LatestArrivalTime) + " " +
+		strconv.Itoa(bcet) + " " +
+		strconv.Itoa(wcet) + " " +
+		strconv.Itoa(job.AbsoluteDeadline) + " " +
+		strconv.Itoa(job.Priority))
+}
 
-				// print the job
-				logger.LogDebug("Job: " + strconv.Itoa(jobSet[len(jobSet)-1].TaskID) + " " +
-					strconv.Itoa(jobSet[len(jobSet)-1].JobID) + " " +
-					strconv.Itoa(jobSet[len(jobSet)-1].EarliestArrivalTime) + " " +
-					strconv.Itoa(jobSet[len(jobSet)-1].LatestArrivalTime) + " " +
-					strconv.Itoa(jobSet[len(jobSet)-1].Vertex.BCET) + " " +
-					strconv.Itoa(jobSet[len(jobSet)-1].Vertex.WCET) + " " +
-					strconv.Itoa(jobSet[len(jobSet)-1].AbsoluteDeadline) + " " +
-					strconv.Itoa(jobSet[len(jobSet)-1].Priority))
+// horizonOf returns the length of the interval of a job set, which is the feasibility interval of the tasks, unless a
+// horizon is set
+func (g *Generator) horizonOf(hyperperiod, maxOffset int) int {
+	if g.horizon > 0 {
+		return g.horizon
+	}
+	return common.FeasibilityInterval(hyperperiod, maxOffset)
+}
 
-				uniqueID++
+// generateJobSet generates the jobs of a task set with the given random generator of the sporadic arrivals
+func (g *Generator) generateJobSet(tasks common.TaskSet, rng *rand.Rand) (common.JobSet, error) {
+	// first we have to calculate the hyperperiod
+	hyperperiod := tasks.HyperPeriod()
+	if hyperperiod == -1 {
+		return nil, fmt.Errorf("error calculating hyperperiod")
+	}
+	horizon := g.horizonOf(hyperperiod, tasks.MaxOffset())
+	priorities, err := g.taskPriorities(tasks)
+	if err != nil {
+		return nil, err
+	}
+	// now we have to generate the job set
+	jobSet := common.JobSet{}
+	for i, task := range tasks {
+		for j, earliestArrivalTime := range g.releases(rng, task.Period, task.Offset, horizon) {
+			latestArrivalTime := earliestArrivalTime + task.Jitter
+			// now we have to calculate the deadline
+			deadline := earliestArrivalTime + task.Deadline
+			// now we have to create the job
+			job := &common.Job{
+				Task:                task,
+				TaskID:              i,
+				JobID:               j,
+				EarliestArrivalTime: earliestArrivalTime,
+				LatestArrivalTime:   latestArrivalTime,
+				AbsoluteDeadline:    deadline,
+				Priority:            jobPriority(priorities, task.TaskID, deadline),
 			}
-
+			jobSet = append(jobSet, job)
+			g.logJob(job, task.BCET, task.WCET)
 		}
-		// now we have to write the job sets to a file
-		// remove folders before file name
-		fileName := filepath.Base(taskPath)
-		// get the taskPath without the file name
-		mainPath := filepath.Dir(taskPath)
-		// remove taskset from the taskPath
-		mainPath = filepath.Dir(mainPath)
-		// add jobsets folder to the taskPath
-		mainPath = filepath.Join(mainPath, "jobsets")
-
-		// add jobset before the file name
-		mainPath = filepath.Join(mainPath, "jobset-"+fileName)
+	}
+	return jobSet, nil
+}
 
-		// Now we have to create the precedence file
-		// first add .prec before the file format
-		precPath = mainPath[:strings.LastIndex(mainPath, ".")] + ".prec." + outputFormat
+// JobSet generates the jobs of the task set with the given index in memory, by default in the feasibility interval
+// of the tasks, i.e., one hyperperiod for synchronous tasks, and the largest offset plus two hyperperiods for tasks
+// with offsets. It is the same job set that WriteJobSets writes for this task set.
+func (g *Generator) JobSet(tasks common.TaskSet, index int) (common.JobSet, error) {
+	return g.generateJobSet(tasks, newRand(g.seed, streamJobSet, g.setKey(index)))
+}
 
-		// create the whole path
-		err = os.MkdirAll(filepath.Dir(mainPath), os.ModePerm)
+// generateVertexJobSet generates the jobs of the vertices of the DAG of a task set with the given random generator of
+// the sporadic arrivals. The jobs of the vertices are numbered one after the other, and the vertices of a task are
+// released together and have the fixed priority of the task.
+func (g *Generator) generateVertexJobSet(tasks common.TaskSet, vertices common.VertexSet,
+	rng *rand.Rand) (common.JobSet, error) {
+	// print the vertices
+	g.logger.LogInfo("Number of vertices in the precedence graph: " + strconv.Itoa(len(vertices)))
+	for i, vertex := range vertices {
+		g.logger.LogDebug("Vertex: " + strconv.Itoa(i) + " " + strconv.Itoa(vertex.VertexID) + " " +
+			strconv.Itoa(vertex.Jitter) + " " + strconv.Itoa(vertex.BCET) + " " + strconv.Itoa(vertex.WCET) + " ")
+	}
 
-		// create the whole path for the precedence file
-		err = os.MkdirAll(filepath.Dir(precPath), os.ModePerm)
+	// calculate the hyperperiod
+	hyperperiod := vertices.HyperPeriod()
+	if hyperperiod == -1 {
+		return nil, fmt.Errorf("error calculating hyperperiod")
+	}
+	horizon := g.horizonOf(hyperperiod, vertices.MaxOffset())
+	priorities, err := g.taskPriorities(tasks)
+	if err != nil {
+		return nil, err
+	}
 
-		if outputFormat == "csv" {
-			err = jobSet.WriteJobSet(mainPath)
-			if err != nil {
-				logger.LogFatal("Error writing job set: " + err.Error())
-			}
-			err = jobSet.WriteDependencyJobSet(precPath)
-			if err != nil {
-				logger.LogFatal("Error writing precedence graph: " + err.Error())
-			}
-		} else {
-			err = jobSet.WriteJobSetYAML(mainPath)
-			if err != nil {
-				logger.LogFatal("Error writing job set: " + err.Error())
+	// now first let's create the job set
+	jobSet := common.JobSet{}
+	uniqueID := 0
+	taskReleases := make(map[int][]int)
+	for _, vertex := range vertices {
+		if _, ok := priorities[vertex.TaskID]; priorities != nil && !ok {
+			return nil, fmt.Errorf("vertex %d belongs to the unknown task %d", vertex.VertexID, vertex.TaskID)
+		}
+		// the releases are drawn once for each task
+		releases, ok := taskReleases[vertex.TaskID]
+		if !ok {
+			releases = g.releases(rng, vertex.Period, vertex.Offset, horizon)
+			taskReleases[vertex.TaskID] = releases
+		}
+		for _, earliestArrivalTime := range releases {
+			latestArrivalTime := earliestArrivalTime + vertex.Jitter
+			// now we have to calculate the deadline
+			deadline := earliestArrivalTime + vertex.Deadline
+			// now we have to create the job
+			job := &common.Job{
+				Vertex:              vertex,
+				TaskID:              vertex.VertexID,
+				JobID:               uniqueID,
+				EarliestArrivalTime: earliestArrivalTime,
+				LatestArrivalTime:   latestArrivalTime,
+				AbsoluteDeadline:    deadline,
+				Priority:            jobPriority(priorities, vertex.TaskID, deadline),
 			}
+			jobSet = append(jobSet, job)
+			g.logJob(job, vertex.BCET, vertex.WCET)
+			uniqueID++
 		}
+	}
+	return jobSet, nil
+}
 
-	} else {
+// VertexJobSet generates the jobs of the vertices of the DAG of the task set with the given index in memory, like
+// JobSet
+func (g *Generator) VertexJobSet(tasks common.TaskSet, vertices common.VertexSet, index int) (common.JobSet, error) {
+	return g.generateVertexJobSet(tasks, vertices, newRand(g.seed, streamJobSet, g.setKey(index)))
+}
 
-		// first we have to read the task set
-		var tasks common.TaskSet
-		var err error
+// writeJobSet generates the jobs of a task set file, or of its DAG if there is one, with the seed of its job set
+// stream and writes them to the "jobsets" folder
+func (g *Generator) writeJobSet(taskSetPath string, seed int64) error {
+	rng := rand.New(rand.NewSource(seed))
+	jobPath := jobSetPath(taskSetPath)
+	if err := os.MkdirAll(filepath.Dir(jobPath), os.ModePerm); err != nil {
+		return err
+	}
 
-		if outputFormat == "csv" {
-			tasks, err = common.ReadTaskSet(taskPath)
-		} else {
-			tasks, err = common.ReadTaskSetYAML(taskPath)
-		}
+	// the task set gives the fixed priorities of the jobs of its DAG too
+	tasks, err := readTaskSet(taskSetPath, g.outputFormat)
+	if err != nil {
+		return fmt.Errorf("error reading task set: %w", err)
+	}
 
+	// first let's see we have a prec file or not
+	if _, err := os.Stat(precPath(taskSetPath, g.outputFormat)); err == nil {
+		// read the precedence graph
+		precGraph, err := readVertexSet(precPath(taskSetPath, g.outputFormat), g.outputFormat)
 		if err != nil {
-			logger.LogFatal("Error reading task set: " + err.Error())
+			return fmt.Errorf("error reading precedence graph: %w", err)
 		}
-		// first we have to calculate the hyperperiod
-		hyperperiod := tasks.HyperPeriod()
-		if hyperperiod == -1 {
-			logger.LogFatal("Error calculating hyperperiod")
+		jobSet, err := g.generateVertexJobSet(tasks, precGraph, rng)
+		if err != nil {
+			return err
 		}
-		// now we have to generate the job set
-		jobSet := common.JobSet{}
-		for i, task := range tasks {
-			// first we have to calculate the number of jobs
-			numJobs := hyperperiod / task.Period
-			for j := 0; j < numJobs; j++ {
-				// now we have to calculate the arrival time
-				earliestArrivalTime := j * task.Period
-				latestArrivalTime := earliestArrivalTime + task.Jitter
-				// now we have to calculate the deadline
-				deadline := earliestArrivalTime + task.Deadline
-				// now we have to calculate the priority
-				priority := 0
-				switch priorityAssignment {
-				case RM:
-					priority = task.Period
-				case DM:
-					priority = task.Deadline
-				case EDF:
-					priority = deadline
-				}
-				// now we have to create the job
-				jobSet = append(jobSet, &common.Job{
-					Task:                task,
-					TaskID:              i,
-					JobID:               j,
-					EarliestArrivalTime: earliestArrivalTime,
-					LatestArrivalTime:   latestArrivalTime,
-					AbsoluteDeadline:    deadline,
-					Priority:            priority,
-				})
 
-				// print the job
-				logger.LogDebug("Job: " + strconv.Itoa(jobSet[len(jobSet)-1].TaskID) + " " +
-					strconv.Itoa(jobSet[len(jobSet)-1].JobID) + " " +
-					strconv.Itoa(jobSet[len(jobSet)-1].EarliestArrivalTime) + " " +
-					strconv.Itoa(jobSet[len(jobSet)-1].LatestArrivalTime) + " " +
-					strconv.Itoa(jobSet[len(jobSet)-1].Task.BCET) + " " +
-					strconv.Itoa(jobSet[len(jobSet)-1].Task.WCET) + " " +
-					strconv.Itoa(jobSet[len(jobSet)-1].AbsoluteDeadline) + " " +
-					strconv.Itoa(jobSet[len(jobSet)-1].Priority))
+		if g.outputFormat == "csv" {
+			if err := jobSet.WriteJobSet(jobPath); err != nil {
+				return fmt.Errorf("error writing job set: %w", err)
 			}
-
+			// the dependencies between the jobs go to the ".prec" file of the job set
+			if err := jobSet.WriteDependencyJobSet(precPath(jobPath, g.outputFormat)); err != nil {
+				return fmt.Errorf("error writing precedence graph: %w", err)
+			}
+			return nil
 		}
-		// now we have to write the job sets to a file
-		// remove folders before file name
-		fileName := filepath.Base(taskPath)
-		// get the taskPath without the file name
-		mainPath := filepath.Dir(taskPath)
-		// remove taskset from the taskPath
-		mainPath = filepath.Dir(mainPath)
-		// add jobsets folder to the taskPath
-		mainPath = filepath.Join(mainPath, "jobsets")
-
-		// add jobset before the file name
-		mainPath = filepath.Join(mainPath, "jobset-"+fileName)
-
-		// create the whole path
-		err = os.MkdirAll(filepath.Dir(mainPath), os.ModePerm)
-		if outputFormat == "csv" {
-			err = jobSet.WriteJobSet(mainPath)
+		if g.outputFormat == "json" {
+			err = jobSet.WriteJobSetJSON(jobPath)
 		} else {
-			err = jobSet.WriteJobSetYAML(mainPath)
+			err = jobSet.WriteJobSetYAML(jobPath)
 		}
 		if err != nil {
-			logger.LogFatal("Error writing job set: " + err.Error())
+			return fmt.Errorf("error writing job set: %w", err)
 		}
+		return nil
 	}
 
+	jobSet, err := g.generateJobSet(tasks, rng)
+	if err != nil {
+		return err
+	}
+	switch g.outputFormat {
+	case "csv":
+		err = jobSet.WriteJobSet(jobPath)
+	case "json":
+		err = jobSet.WriteJobSetJSON(jobPath)
+	default:
+		err = jobSet.WriteJobSetYAML(jobPath)
+	}
+	if err != nil {
+		return fmt.Errorf("error writing job set: %w", err)
+	}
+	// with deadlines larger than the periods, the jobs of a task overlap and have to keep their order
+	if g.outputFormat == "csv" && jobSet.HasDependencies() {
+		if err := jobSet.WriteDependencyJobSet(precPath(jobPath, g.outputFormat)); err != nil {
+			return fmt.Errorf("error writing precedence graph: %w", err)
+		}
+	}
+	return nil
 }
 
-// GenerateJobSets generates job sets for each task set in the task set folder
-func GenerateJobSets(taskSetPath string, priorityAssignment int, outputFormat string) {
+// WriteJobSets generates the job set of each task set in the root folder.
+// The job sets that already exist are kept.
+func (g *Generator) WriteJobSets(root string) error {
 	// first we have to find all the task sets
-	taskSetPaths := findTaskSetPaths(taskSetPath, outputFormat)
+	taskSetPaths, err := findTaskSetPaths(root, g.outputFormat)
+	if err != nil {
+		return err
+	}
 
-	// now we have to generate the job sets
-	for _, taskSetPath := range taskSetPaths {
-		// make sure that the task set is not generated before
-		// remove folders before file name
-		fileName := filepath.Base(taskSetPath)
-		// get the taskPath without the file name
-		mainPath := filepath.Dir(taskSetPath)
-		// remove taskset from the taskPath
-		mainPath = filepath.Dir(mainPath)
-		// add jobsets folder to the taskPath
-		mainPath = filepath.Join(mainPath, "jobsets")
-		// add jobset before the file name
-		mainPath = filepath.Join(mainPath, "jobset-"+fileName)
-		if _, err := os.Stat(mainPath); os.IsNotExist(err) {
-			logger.LogInfo("Generating job set for: " + taskSetPath)
-			generateJobSet(taskSetPath, priorityAssignment, outputFormat)
+	errs := make([]error, len(taskSetPaths))
+	create := func(i int) {
+		path := taskSetPaths[i]
+		// make sure that the job set is not generated before
+		if _, err := os.Stat(jobSetPath(path)); os.IsNotExist(err) {
+			g.logger.LogInfo("Generating job set for: " + path)
+			seed := deriveSeed(g.seed, streamJobSet, seedKey(root, path))
+			if err := g.writeJobSet(path, seed); err != nil {
+				errs[i] = fmt.Errorf("%s: %w", path, err)
+			}
 		} else {
-			logger.LogInfo("Job set for " + taskSetPath + " exists")
+			g.logger.LogInfo("Job set for " + path + " exists")
 		}
 	}
-}
 
-// GenerateJobSetsParallel generates job sets for each task set in the task set folder in parallel
-func GenerateJobSetsParallel(taskSetPath string, priorityAssignment int, outputFormat string) {
-	// first we have to find all the task sets with csv extension in
-	taskSetPaths := findTaskSetPaths(taskSetPath, outputFormat)
-
-	// now we have to generate the job sets in parallel
-	var wg sync.WaitGroup
-	wg.Add(len(taskSetPaths))
-	for i := 0; i < len(taskSetPaths); i++ {
-		go func(setIndex int) {
-			defer wg.Done()
-			// make sure that the task set is not generated before
-			// remove folders before file name
-			fileName := filepath.Base(taskSetPaths[setIndex])
-			// get the taskPath without the file name
-			mainPath := filepath.Dir(taskSetPaths[setIndex])
-			// remove taskset from the taskPath
-			mainPath = filepath.Dir(mainPath)
-			// add jobsets folder to the taskPath
-			mainPath = filepath.Join(mainPath, "jobsets")
-			// add jobset before the file name
-			mainPath = filepath.Join(mainPath, "jobset-"+fileName)
-			if _, err := os.Stat(mainPath); os.IsNotExist(err) {
-				generateJobSet(taskSetPaths[setIndex], priorityAssignment, outputFormat)
-			} else {
-				logger.LogInfo("Job set for " + taskSetPaths[setIndex] + " exists")
-			}
-		}(i)
+	if g.parallel {
+		var wg sync.WaitGroup
+		wg.Add(len(taskSetPaths))
+		for i := range taskSetPaths {
+			go func(setIndex int) {
+				defer wg.Done()
+				create(setIndex)
+			}(i)
+		}
+		wg.Wait()
+	} else {
+		for i := range taskSetPaths {
+			create(i)
+		}
 	}
-	wg.Wait()
+	return errors.Join(errs...)
 }
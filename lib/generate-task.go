@@ -1,81 +1,89 @@
 package lib
 
 import (
+	"errors"
 	"fmt"
 	"github.com/schollz/progressbar/v3"
 	"math/rand"
 	"os"
 	"path/filepath"
-	"sort"
 	"sync"
+	"task-generator/lib/analysis"
 	"task-generator/lib/common"
-	"time"
 )
 
-var logger *common.VerboseLogger
-var bar *progressbar.ProgressBar
-
-// create a task set
-func createTaskSet(path string, numCore, nTasks int, seed int64, totalUtilization float64, utilDist string,
-	utilBound []float64, periodDist string, periodRange []int, disPeriods []int, alpha float64, jitter float64,
-	constantJitter bool, maxJobs int, mappingHeuristic int, outputFormat string) error {
-	rand.Seed(seed)
+// generateTaskSet generates a task set with the given random generator, which passes the filter if there is one.
+// It also returns the number of attempts, i.e., how many times the task set is generated until it is accepted, and
+// how many of them are rejected by the filter.
+func (g *Generator) generateTaskSet(rng *rand.Rand) (common.TaskSet, int, int, error) {
+	attempts, rejected := 0, 0
+	for {
+		tasks, n, err := g.generateCandidate(rng)
+		attempts += n
+		if err != nil || g.filter == nil {
+			return tasks, attempts, rejected, err
+		}
+		accepted, err := g.filter.accepts(tasks, g.numCores)
+		if err != nil {
+			return nil, attempts, rejected, fmt.Errorf("error filtering task set: %w", err)
+		}
+		if accepted {
+			return tasks, attempts, rejected, nil
+		}
+		rejected++
+		if g.filter.maxAttempts > 0 && rejected >= g.filter.maxAttempts {
+			return nil, attempts, rejected, fmt.Errorf("%w in %d attempts", errFilterExhausted, rejected)
+		}
+		g.logger.LogInfo("Regenerating task set because of the filter")
+	}
+}
 
+// generateCandidate generates a task set with the given random generator, without the filter.
+// It also returns the number of attempts, i.e., how many times the task set is generated until it is accepted.
+func (g *Generator) generateCandidate(rng *rand.Rand) (common.TaskSet, int, error) {
 	tasks := common.TaskSet{}
 	var periods []int
 	var util []float64
+	attempts := 0
 	for {
+		attempts++
 		// clear tasks
-		periods = periods[:0]
-		util = util[:0]
 		tasks = tasks[:0]
-		// First, we generate the utilization
-		if utilDist == "uunifast" {
-			// 1.UUnifastDiscard algorithm
-			util = uunifastDiscard(nTasks, totalUtilization, 1.0)
-		} else if utilDist == "rand-fixed-sum" {
-			// 2. RandFixedSum algorithm
-			util = StaffordRandFixedSum(nTasks, totalUtilization, utilBound[0], utilBound[1])
-		} else if utilDist == "automotive" && periodDist == "automotive" {
-			// 3. Automotive method
-			tasks := generateAutomotiveTaskSet(totalUtilization)
-			for _, task := range tasks {
-				periods = append(periods, task[0])
-				util = append(util, float64(task[1])/float64(task[0]))
-			}
+		var err error
+		// First, we generate the utilization, and the periods if they come with it
+		if g.utilJoint != nil {
+			util, periods, err = g.utilJoint.Tasks(rng, g.utilizationParams())
 		} else {
-			logger.LogFatal(fmt.Sprintf("Unknown utilization distribution: %s", utilDist))
+			util, err = g.utilGen.Utilizations(rng, g.utilizationParams())
+			if err == nil && len(util) != g.numTasks {
+				err = fmt.Errorf("%d utilizations for %d tasks", len(util), g.numTasks)
+			}
+		}
+		if err != nil {
+			return nil, attempts, fmt.Errorf("error generating utilizations with %s: %w", g.utilDist, err)
 		}
 
 		// now we generate the periods
-		if periodDist == "uniform" {
-			// 1. Uniform distribution
-			periods = generatePeriodsUniform(nTasks, float64(periodRange[0]), float64(periodRange[1]))
-		} else if periodDist == "log-uniform" {
-			// 2. Log-uniform distribution
-			periods = generatePeriodsLogUniform(nTasks, float64(periodRange[0]), float64(periodRange[1]))
-		} else if periodDist == "uniform-discrete" {
-			// 3. Uniform discrete distribution
-			periods = generatePeriodsUniformDiscrete(nTasks, float64(periodRange[0]), float64(periodRange[1]), disPeriods)
-		} else if periodDist == "log-uniform-discrete" {
-			// 4. Log-uniform discrete distribution
-			periods = generatePeriodsLogUniformDiscrete(nTasks, float64(periodRange[0]), float64(periodRange[1]), disPeriods)
-		} else if periodDist == "automotive" {
-			if utilDist != "automotive" {
-				// 5. Automotive method
-				tasks := generateAutomotiveTaskSet(totalUtilization)
-				for _, task := range tasks {
-					periods = append(periods, task[0])
-				}
+		if g.periodJoint != nil {
+			if g.utilJoint == nil {
+				_, periods, err = g.periodJoint.Tasks(rng, g.jointPeriodParams())
 			}
 		} else {
-			logger.LogFatal(fmt.Sprintf("Unknown period distribution: %s", periodDist))
+			periods, err = g.periodGen.Periods(rng, g.periodParams())
+		}
+		if err != nil {
+			return nil, attempts, fmt.Errorf("error generating periods with %s: %w", g.periodDist, err)
+		}
+
+		if len(periods) < len(util) {
+			return nil, attempts, fmt.Errorf("error generating task set: %d periods for %d utilizations",
+				len(periods), len(util))
 		}
 
 		scale := 10
 		for i, u := range util {
 			wcet := int(float64(periods[i]) * u * float64(scale))
-			bcet := int(alpha * float64(wcet))
+			bcet := int(g.execVariation * float64(wcet))
 			period := int(float64(periods[i]) * float64(scale))
 			tasks = append(tasks, &common.Task{
 				Period:   period,
@@ -91,164 +99,181 @@ func createTaskSet(path string, numCore, nTasks int, seed int64, totalUtilizatio
 		for _, task := range tasks {
 			if task.WCET == 0 {
 				flag = false
-				logger.LogInfo("Regenerating task set because of zero WCET")
+				g.logger.LogInfo("Regenerating task set because of zero WCET")
 				break
 			}
-			if constantJitter {
-				task.Jitter = int(jitter)
+			if g.constantJitter {
+				task.Jitter = int(g.jitter)
 			} else {
-				task.Jitter = int(jitter * float64(task.Period))
+				task.Jitter = int(g.jitter * float64(task.Period))
 			}
 			if task.Period < task.WCET+task.Jitter {
 				flag = false
-				logger.LogInfo("Regenerating task set because of period less than WCET + Jitter")
+				g.logger.LogInfo("Regenerating task set because of period less than WCET + Jitter")
 				break
 			}
 
 		}
 		// now we check the number of jobs in the hyperperiod
-		if maxJobs > 0 {
+		if flag && g.maxJobs > 0 {
 			// If this took too long, we need to regenerate the task set
 			// get the hyperperiod
 			hyperperiod := tasks.HyperPeriod()
 			if hyperperiod == -1 {
 				// this means that the hyperperiod is too large
 				flag = false
-				logger.LogInfo("Regenerating task set because of large hyperperiod")
-			} else {
-				// get the number of jobs
-				numJobs := tasks.NumJobs(hyperperiod)
-				if numJobs > maxJobs {
-					// this means that the number of jobs is too large
-					flag = false
-					logger.LogInfo("Regenerating task set because of large number of jobs")
-				}
+				g.logger.LogInfo("Regenerating task set because of large hyperperiod")
+			} else if numJobs := tasks.NumJobs(hyperperiod); numJobs > g.maxJobs {
+				// this means that the number of jobs is too large
+				flag = false
+				g.logger.LogInfo("Regenerating task set because of large number of jobs")
 			}
 		}
 		if flag {
 			break
 		}
-
-		if utilDist != "automotive" && periodDist != "automotive" {
-			if len(util) != nTasks || len(periods) != nTasks || len(tasks) != nTasks {
-				logger.LogFatal("Error generating task set")
-			}
-		}
 	}
 
 	// sort the tasks by period
 	tasks.SortByPeriod()
+	// the task IDs follow the order in the set, as in the written files
+	for i, task := range tasks {
+		task.TaskID = i
+	}
+	// the deadlines are drawn last, so the other parameters do not depend on the deadline model
+	generateDeadlines(rng, tasks, g.deadlineModel, g.deadlineFactor)
+	generateOffsets(rng, tasks, g.offsetModel, g.offsetGranularity)
+	generateCriticality(rng, tasks, g.hiFraction, g.hiWCETFactor)
 
 	// Now we have to map the tasks if it is necessary
-	tasks.MapTasks(numCore, mappingHeuristic)
-	// create the whole path
-	err := os.MkdirAll(filepath.Dir(path), os.ModePerm)
+	tasks.MapTasks(g.numCores, g.mappingHeuristic)
+	// the priorities of OPA depend on the whole set and its mapping, so they are written with the set
+	if g.priorityAssignment == OPA {
+		priorities, err := analysis.Priorities(tasks, analysis.OPA)
+		if err != nil {
+			return nil, attempts, err
+		}
+		for i, task := range tasks {
+			task.Priority = priorities[i]
+		}
+	}
+	return tasks, attempts, nil
+}
+
+// TaskSet generates the task set with the given index in memory.
+// It is the same task set that WriteTaskSets writes with this index.
+func (g *Generator) TaskSet(index int) (common.TaskSet, error) {
+	tasks, _, _, err := g.generateTaskSet(newRand(g.seed, streamTaskSet, g.setKey(index)))
+	return tasks, err
+}
 
+// writeTaskSet generates the task set with the given index and writes it with its manifest
+func (g *Generator) writeTaskSet(path string, index int) error {
+	key := g.setKey(index)
+	seed := deriveSeed(g.seed, streamTaskSet, key)
+	tasks, attempts, rejected, err := g.generateTaskSet(rand.New(rand.NewSource(seed)))
 	if err != nil {
 		return err
 	}
-	// write the task set to a CSV file
-	if outputFormat == "csv" {
-		err = tasks.WriteTaskSet(path)
-		if err != nil {
-			return err
-		}
-	} else if outputFormat == "yaml" {
-		err = tasks.WriteTaskSetYAML(path)
 
+	// create the whole path
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+	if err := writeTaskSet(tasks, path, g.outputFormat); err != nil {
+		return err
 	}
-	return nil
-}
 
-// CreateTaskSets creates a number of task sets and writes them to the specified path
-func CreateTaskSets(path string, numCore, numSets int, tasks int, utilization float64, utilDistribution string,
-	utilBound []float64, periodDistribution string, periodRange []int, disPeriods []int, execVariation float64,
-	jitter float64, constantJitter bool, maxJobs int, mappingHeuristic int, outputFormat string, lr *common.VerboseLogger) {
-	// add spec to the path before output folder
-	path = filepath.Join(path, fmt.Sprintf("%s-utilDist", utilDistribution))
-	path = filepath.Join(path, fmt.Sprintf("%s-perDist", periodDistribution))
-	path = filepath.Join(path, fmt.Sprintf("%d-core", numCore))
-	path = filepath.Join(path, fmt.Sprintf("%d-task", tasks))
-	if constantJitter {
-		path = filepath.Join(path, fmt.Sprintf("%d-jitter", int(jitter)))
-	} else {
-		path = filepath.Join(path, fmt.Sprintf("%d-percent-jitter", int(jitter*100)))
+	// finally, we record how the task set is generated
+	hyperperiod := tasks.HyperPeriod()
+	numJobs := -1
+	if hyperperiod != -1 {
+		numJobs = tasks.NumJobs(common.FeasibilityInterval(hyperperiod, tasks.MaxOffset()))
 	}
-	path = filepath.Join(path, fmt.Sprintf("%.2f-util", utilization))
-	path = filepath.Join(path, "tasksets")
-	// sort disPeriods
-	// from large to small
-	sort.Slice(disPeriods, func(i, j int) bool {
-		return disPeriods[i] > disPeriods[j]
+	return writeManifest(path, &Manifest{
+		File:             filepath.Base(path),
+		Key:              key,
+		Seed:             seed,
+		MasterSeed:       g.seed,
+		GeneratorVersion: Version,
+		ConfigHash:       g.provenance.ConfigHash,
+		Attempts:         attempts,
+		Rejected:         rejected,
+		Utilization:      tasks.Utilization(),
+		Hyperperiod:      hyperperiod,
+		NumJobs:          numJobs,
+		Cores:            g.numCores,
+		Config:           g.provenance.Config,
 	})
-	logger = lr
-	if lr.GetVerboseLevel() == common.VerboseLevelNone {
+}
+
+// WriteTaskSets generates a number of task sets and writes them to the folder Dir in the root folder.
+// The task sets that already exist are kept. With a filter, a task set that does not pass it within the maximum
+// number of attempts is not written, and the acceptance ratio of the filter is reported in the root folder.
+func (g *Generator) WriteTaskSets(root string, numSets int) error {
+	path := filepath.Join(root, filepath.FromSlash(g.Dir()), "tasksets")
+	if g.outputFormat == "json" {
+		// the JSON files can be validated with their schemas
+		if err := common.WriteSchemas(filepath.Join(root, "schemas")); err != nil {
+			return fmt.Errorf("cannot write the JSON schemas: %w", err)
+		}
+	}
+
+	var bar *progressbar.ProgressBar
+	if g.progressBar && !g.parallel {
 		bar = progressbar.Default(int64(numSets))
 	}
-	for i := 0; i < numSets; i++ {
-		file := fmt.Sprintf("%s_%d.%s", periodDistribution, i, outputFormat)
+
+	errs := make([]error, numSets)
+	taskSetPaths := make([]string, numSets)
+	exhausted := make([]bool, numSets)
+	create := func(setIndex int) {
+		file := fmt.Sprintf("%s_%d.%s", g.periodDist, setIndex, g.outputFormat)
 		taskSetPath := filepath.Join(path, file)
+		taskSetPaths[setIndex] = taskSetPath
 		if _, err := os.Stat(taskSetPath); os.IsNotExist(err) {
-			if err := createTaskSet(taskSetPath, numCore, tasks, time.Now().UnixNano(), utilization, utilDistribution, utilBound,
-				periodDistribution, periodRange, disPeriods, execVariation, jitter, constantJitter,
-				maxJobs, mappingHeuristic, outputFormat); err != nil {
-				fmt.Println(err)
+			err := g.writeTaskSet(taskSetPath, setIndex)
+			if errors.Is(err, errFilterExhausted) {
+				// the other sets of the point are still written, and the missing one shows in the acceptance report
+				exhausted[setIndex] = true
+				g.logger.LogWarning(fmt.Sprintf("%s: %v", taskSetPath, err))
+			} else if err != nil {
+				errs[setIndex] = fmt.Errorf("%s: %w", taskSetPath, err)
 			} else {
-				logger.LogInfo(fmt.Sprintf("%s created", taskSetPath))
+				g.logger.LogInfo(fmt.Sprintf("%s created", taskSetPath))
 			}
 		} else {
-			logger.LogInfo(fmt.Sprintf("%s exists", taskSetPath))
-		}
-		if lr.GetVerboseLevel() == common.VerboseLevelNone {
-			bar.Add(1)
+			g.logger.LogInfo(fmt.Sprintf("%s exists", taskSetPath))
 		}
 	}
-}
 
-// CreateTaskSetsParallel creates task sets in parallel using the given parameters
-func CreateTaskSetsParallel(path string, numCore, numSets int, tasks int, utilization float64, utilDistribution string,
-	utilBound []float64, periodDistribution string, periodRange []int, disPeriods []int, execVariation float64,
-	jitter float64, constantJitter bool, maxJobs int, mappingHeuristic int, outputFormat string, lr *common.VerboseLogger) {
-	// add spec to the path before output folder
-	path = filepath.Join(path, fmt.Sprintf("%s-utilDist", utilDistribution))
-	path = filepath.Join(path, fmt.Sprintf("%s-perDist", periodDistribution))
-	path = filepath.Join(path, fmt.Sprintf("%d-core", numCore))
-	path = filepath.Join(path, fmt.Sprintf("%d-task", tasks))
-	if constantJitter {
-		path = filepath.Join(path, fmt.Sprintf("%d-jitter", int(jitter)))
+	if g.parallel {
+		var wg sync.WaitGroup
+		wg.Add(numSets)
+		for i := 0; i < numSets; i++ {
+			go func(setIndex int) {
+				defer wg.Done()
+				create(setIndex)
+			}(i)
+		}
+		wg.Wait()
 	} else {
-		path = filepath.Join(path, fmt.Sprintf("%d-percent-jitter", int(jitter*100)))
-	}
-	path = filepath.Join(path, fmt.Sprintf("%.2f-util", utilization))
-	path = filepath.Join(path, "tasksets")
-
-	// sort disPeriods
-	// from large to small
-	sort.Slice(disPeriods, func(i, j int) bool {
-		return disPeriods[i] > disPeriods[j]
-	})
-	var wg sync.WaitGroup
-	wg.Add(numSets)
-	logger = lr
-
-	for i := 0; i < numSets; i++ {
-		go func(setIndex int) {
-			defer wg.Done()
-			file := fmt.Sprintf("%s_%d.%s", periodDistribution, setIndex, outputFormat)
-			taskSetPath := filepath.Join(path, file)
-			if _, err := os.Stat(taskSetPath); os.IsNotExist(err) {
-				if err := createTaskSet(taskSetPath, numCore, tasks, time.Now().UnixNano(), utilization, utilDistribution,
-					utilBound, periodDistribution, periodRange, disPeriods, execVariation, jitter,
-					constantJitter, maxJobs, mappingHeuristic, outputFormat); err != nil {
-					fmt.Println(err)
-				} else {
-					logger.LogInfo(fmt.Sprintf("%s created", taskSetPath))
-				}
-			} else {
-				logger.LogInfo(fmt.Sprintf("%s exists", taskSetPath))
+		for i := 0; i < numSets; i++ {
+			create(i)
+			if bar != nil {
+				bar.Add(1)
 			}
-		}(i)
+		}
 	}
 
-	wg.Wait()
+	// the index is written once all the sets are there
+	if err := writeManifestIndex(path); err != nil {
+		errs = append(errs, fmt.Errorf("error writing manifest index: %w", err))
+	}
+	if g.filter != nil {
+		if err := g.writeAcceptance(root, taskSetPaths, exhausted); err != nil {
+			errs = append(errs, fmt.Errorf("error writing acceptance report: %w", err))
+		}
+	}
+	return errors.Join(errs...)
 }
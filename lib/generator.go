@@ -0,0 +1,469 @@
+package lib
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"task-generator/lib/common"
+	"time"
+)
+
+// Generator generates task sets, DAGs, and job sets.
+// A generator is configured with options and is safe to use from several goroutines.
+type Generator struct {
+	seed               int64
+	numCores           int
+	numTasks           int
+	utilization        float64
+	utilDist           string
+	utilBound          []float64
+	utilLowerBounds    []float64
+	utilUpperBounds    []float64
+	periodDist         string
+	periodRange        []int
+	periods            []int
+	harmonicBase       int
+	harmonicMult       []int
+	harmonicChains     int
+	periodFactors      [][]int
+	execVariation      float64
+	deadlineModel      string
+	deadlineFactor     float64
+	offsetModel        string
+	offsetGranularity  int
+	arrivalModel       string
+	arrivalDelay       float64
+	burstLength        float64
+	horizon            int
+	hiFraction         float64
+	hiWCETFactor       float64
+	jitter             float64
+	constantJitter     bool
+	maxJobs            int
+	mappingHeuristic   int
+	dagType            string
+	forkProb           float64
+	edgeProb           float64
+	maxBranches        int
+	maxVertices        int
+	numRoots           int
+	maxDepth           int
+	priorityAssignment int
+	outputFormat       string
+	exports            []string
+	simsoScheduler     string
+	filter             *filter
+	utilGen            UtilizationGenerator
+	utilJoint          JointGenerator
+	periodGen          PeriodGenerator
+	periodJoint        JointGenerator
+	parallel           bool
+	progressBar        bool
+	provenance         Provenance
+	logger             *common.VerboseLogger
+}
+
+// Option configures a Generator
+type Option func(*Generator)
+
+// WithSeed sets the master seed. Without this option, a random seed is taken (see Seed).
+func WithSeed(seed int64) Option {
+	return func(g *Generator) {
+		g.seed = seed
+	}
+}
+
+// WithCores sets the number of cores that the tasks are mapped to
+func WithCores(numCores int) Option {
+	return func(g *Generator) {
+		g.numCores = numCores
+	}
+}
+
+// WithTasks sets the number of tasks of each task set
+func WithTasks(numTasks int) Option {
+	return func(g *Generator) {
+		g.numTasks = numTasks
+	}
+}
+
+// WithUtilization sets the total utilization of each task set
+func WithUtilization(utilization float64) Option {
+	return func(g *Generator) {
+		g.utilization = utilization
+	}
+}
+
+// WithUtilizationDistribution sets the utilization distribution by its name: "uunifast", "rand-fixed-sum", "drs",
+// "automotive", or a registered one (see RegisterUtilizationGenerator).
+// The bounds [min, max] of the utilization of each task are only used by "rand-fixed-sum" and "drs".
+func WithUtilizationDistribution(name string, bounds ...float64) Option {
+	return func(g *Generator) {
+		g.utilDist = name
+		g.utilBound = bounds
+	}
+}
+
+// WithUtilizationTaskBounds sets a lower and an upper bound of the utilization of each task in turn, e.g., a lower
+// bound [0.6] and an upper bound [1.0, 0.1] give one task of at least 0.6 and the others of at most 0.1.
+// A shorter list is extended with its last value. The bounds are only used by "drs".
+func WithUtilizationTaskBounds(lower, upper []float64) Option {
+	return func(g *Generator) {
+		g.utilLowerBounds = lower
+		g.utilUpperBounds = upper
+	}
+}
+
+// WithPeriodDistribution sets the period distribution by its name: "uniform", "log-uniform", "uniform-discrete",
+// "log-uniform-discrete", "harmonic", "k-harmonic-chains" (see WithHarmonicPeriods), "divisors" (see
+// WithPeriodFactors), "automotive", or a registered one (see RegisterPeriodGenerator).
+// The discrete distributions round the periods down to the given periods.
+func WithPeriodDistribution(name string, periodRange []int, periods []int) Option {
+	return func(g *Generator) {
+		g.periodDist = name
+		g.periodRange = periodRange
+		g.periods = periods
+	}
+}
+
+// WithHarmonicPeriods sets the first period (by default the smallest period of the range), the multipliers between
+// two successive periods (by default [1, 2]), and the number of chains (by default 2) of the "harmonic" and
+// "k-harmonic-chains" period distributions
+func WithHarmonicPeriods(base int, multipliers []int, chains int) Option {
+	return func(g *Generator) {
+		g.harmonicBase = base
+		g.harmonicMult = multipliers
+		g.harmonicChains = chains
+	}
+}
+
+// WithPeriodFactors sets the factor matrix of the "divisors" period distribution: each period is the product of one
+// factor of each row, e.g., [[1, 2, 4], [1, 3]] gives periods that divide 12. By default, the periods divide 75600.
+func WithPeriodFactors(factors [][]int) Option {
+	return func(g *Generator) {
+		g.periodFactors = factors
+	}
+}
+
+// WithExecVariation sets the ratio of the BCET to the WCET of the tasks
+func WithExecVariation(execVariation float64) Option {
+	return func(g *Generator) {
+		g.execVariation = execVariation
+	}
+}
+
+// WithDeadlines sets the deadline model of the tasks: "implicit" (D = T), "constrained" (D in [C + J, T], or in
+// [max(factor * T, C + J), T] with a factor in (0, 1]), or "arbitrary" (D in [C + J, factor * T] with a factor of at least 1)
+func WithDeadlines(model string, factor float64) Option {
+	return func(g *Generator) {
+		g.deadlineModel = model
+		g.deadlineFactor = factor
+	}
+}
+
+// WithOffsets sets the release offsets of the tasks: "zero" (synchronous tasks), "uniform" (in [0, T)), or
+// "granular" (a multiple of the granularity in [0, T))
+func WithOffsets(model string, granularity int) Option {
+	return func(g *Generator) {
+		g.offsetModel = model
+		g.offsetGranularity = granularity
+	}
+}
+
+// WithArrivals sets how the jobs of the tasks arrive: "periodic", or sporadic with a delay X after the period T
+// between two releases, drawn from [0, delay * T] ("uniform"), with the mean delay * T ("exponential"), or in bursts of
+// burstLength jobs on average that are separated by longer delays ("bursty")
+func WithArrivals(model string, delay, burstLength float64) Option {
+	return func(g *Generator) {
+		g.arrivalModel = model
+		g.arrivalDelay = delay
+		g.burstLength = burstLength
+	}
+}
+
+// WithHorizon sets the length of the interval of the job sets. Without a horizon, the job sets cover the feasibility
+// interval of the tasks.
+func WithHorizon(horizon int) Option {
+	return func(g *Generator) {
+		g.horizon = horizon
+	}
+}
+
+// WithMixedCriticality makes a fraction of the tasks HI tasks (CP), whose HI WCET is wcetFactor times their LO WCET
+// (CF). The utilization of the task set is its utilization in the LO mode.
+func WithMixedCriticality(hiFraction, wcetFactor float64) Option {
+	return func(g *Generator) {
+		g.hiFraction = hiFraction
+		g.hiWCETFactor = wcetFactor
+	}
+}
+
+// WithJitter sets the release jitter of the tasks, in time units if constant or as a ratio of the period otherwise
+func WithJitter(jitter float64, constant bool) Option {
+	return func(g *Generator) {
+		g.jitter = jitter
+		g.constantJitter = constant
+	}
+}
+
+// WithMaxJobs sets the maximum number of jobs in the hyperperiod of a task set (0: no limit)
+func WithMaxJobs(maxJobs int) Option {
+	return func(g *Generator) {
+		g.maxJobs = maxJobs
+	}
+}
+
+// WithMappingHeuristic sets the heuristic to map the tasks to the cores:
+// 0. No mapping, 1. Worst-fit, 2. Best-fit, 3. First-fit
+func WithMappingHeuristic(heuristic int) Option {
+	return func(g *Generator) {
+		g.mappingHeuristic = heuristic
+	}
+}
+
+// WithForkJoinDAG generates a fork-join DAG for each task
+func WithForkJoinDAG(forkProb, edgeProb float64, maxBranches, maxVertices, maxDepth int) Option {
+	return func(g *Generator) {
+		g.dagType = "fork-join"
+		g.forkProb = forkProb
+		g.edgeProb = edgeProb
+		g.maxBranches = maxBranches
+		g.maxVertices = maxVertices
+		g.maxDepth = maxDepth
+	}
+}
+
+// WithRandomDAG generates a random DAG whose vertices are the tasks of a task set
+func WithRandomDAG(numRoots, maxBranches, maxDepth int) Option {
+	return func(g *Generator) {
+		g.dagType = "random"
+		g.numRoots = numRoots
+		g.maxBranches = maxBranches
+		g.maxDepth = maxDepth
+	}
+}
+
+// WithTaskChain generates a chain of all the tasks of a task set
+func WithTaskChain() Option {
+	return func(g *Generator) {
+		g.dagType = "chain"
+	}
+}
+
+// WithPriorityAssignment sets the priority assignment of the job sets: RM, DM, EDF, DkC, OPA, or Explicit for the
+// priorities of the task sets
+func WithPriorityAssignment(priorityAssignment int) Option {
+	return func(g *Generator) {
+		g.priorityAssignment = priorityAssignment
+	}
+}
+
+// WithFilter keeps only the task sets that the schedulability test of the analysis (see AnalysisTests) finds
+// schedulable, or unschedulable, on the cores of the generator. The priority policy of the fixed-priority tests is
+// "RM" or "DM". A task set is regenerated until it passes the filter, at most maxAttempts times (0: no limit).
+func WithFilter(test, policy string, schedulable bool, maxAttempts int) Option {
+	return func(g *Generator) {
+		g.filter = &filter{test: test, policy: policy, schedulable: schedulable, maxAttempts: maxAttempts}
+	}
+}
+
+// WithOutputFormat sets the format of the written files: "csv", "yaml", or "json"
+func WithOutputFormat(format string) Option {
+	return func(g *Generator) {
+		g.outputFormat = format
+	}
+}
+
+// WithExports sets the formats that WriteExports exports the task sets to (see ExportFormats)
+func WithExports(formats []string) Option {
+	return func(g *Generator) {
+		g.exports = formats
+	}
+}
+
+// WithSimSoScheduler sets the scheduler class of the SimSo exports, e.g., "simso.schedulers.EDF" for global EDF. By
+// default, it follows the priority assignment (see export.SimSoScheduler).
+func WithSimSoScheduler(scheduler string) Option {
+	return func(g *Generator) {
+		g.simsoScheduler = scheduler
+	}
+}
+
+// WithParallel writes the sets in parallel
+func WithParallel(parallel bool) Option {
+	return func(g *Generator) {
+		g.parallel = parallel
+	}
+}
+
+// WithProgressBar shows a progress bar while the task sets are written sequentially
+func WithProgressBar(progressBar bool) Option {
+	return func(g *Generator) {
+		g.progressBar = progressBar
+	}
+}
+
+// WithProvenance sets the provenance that is recorded in the manifest of each written task set
+func WithProvenance(provenance Provenance) Option {
+	return func(g *Generator) {
+		g.provenance = provenance
+	}
+}
+
+// WithLogger sets the logger of the generator. Without this option, nothing is logged.
+func WithLogger(logger *common.VerboseLogger) Option {
+	return func(g *Generator) {
+		g.logger = logger
+	}
+}
+
+// NewGenerator creates a generator with the given options and checks them
+func NewGenerator(options ...Option) (*Generator, error) {
+	g := &Generator{
+		seed:         time.Now().UnixNano(),
+		numCores:     1,
+		utilDist:     "uunifast",
+		periodDist:   "uniform",
+		outputFormat: "csv",
+		logger:       common.NewVerboseLogger("", common.VerboseLevelNone),
+	}
+	for _, option := range options {
+		option(g)
+	}
+
+	if g.outputFormat != "csv" && g.outputFormat != "yaml" && g.outputFormat != "json" {
+		return nil, fmt.Errorf("invalid output format: %s", g.outputFormat)
+	}
+	if g.numCores < 1 {
+		return nil, fmt.Errorf("the number of cores should be positive")
+	}
+	if err := checkDeadlineModel(g.deadlineModel, g.deadlineFactor); err != nil {
+		return nil, err
+	}
+	if err := checkOffsetModel(g.offsetModel, g.offsetGranularity); err != nil {
+		return nil, err
+	}
+	if err := checkArrivalModel(g.arrivalModel, g.arrivalDelay, g.burstLength); err != nil {
+		return nil, err
+	}
+	if err := checkCriticality(g.hiFraction, g.hiWCETFactor); err != nil {
+		return nil, err
+	}
+	if _, ok := fixedPriorityPolicies[g.priorityAssignment]; !ok && g.priorityAssignment != EDF {
+		return nil, fmt.Errorf("invalid priority assignment: %d", g.priorityAssignment)
+	}
+	if err := checkExports(g.exports); err != nil {
+		return nil, err
+	}
+	if err := checkFilter(g.filter); err != nil {
+		return nil, err
+	}
+	if g.horizon < 0 {
+		return nil, fmt.Errorf("the horizon should not be negative")
+	}
+
+	// sort the discrete periods from large to small, without changing the caller's slice
+	g.periods = append([]int(nil), g.periods...)
+	sort.Slice(g.periods, func(i, j int) bool {
+		return g.periods[i] > g.periods[j]
+	})
+
+	// find the distributions in the registry
+	var err error
+	if g.utilGen, g.utilJoint, err = lookupUtilization(g.utilDist); err != nil {
+		return nil, err
+	}
+	if g.periodGen, g.periodJoint, err = lookupPeriod(g.periodDist); err != nil {
+		return nil, err
+	}
+	if g.utilJoint != nil {
+		// the periods come with the utilizations
+		if g.periodDist != g.utilDist {
+			return nil, fmt.Errorf("the utilization distribution is %s but the period distribution is not %s",
+				g.utilDist, g.utilDist)
+		}
+		// Print a warning that the joint method does not consider the number of tasks
+		g.logger.LogWarning(fmt.Sprintf("The %s method does not consider the number of tasks", g.utilDist))
+		err = g.utilJoint.Check(g.utilizationParams())
+	} else {
+		if g.numTasks < 1 {
+			return nil, fmt.Errorf("the number of tasks should be positive")
+		}
+		err = g.utilGen.Check(g.utilizationParams())
+		if err == nil && g.periodJoint != nil {
+			err = g.periodJoint.Check(g.jointPeriodParams())
+		} else if err == nil {
+			err = g.periodGen.Check(g.periodParams())
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch g.dagType {
+	case "", "fork-join", "chain":
+	case "random":
+		if g.maxDepth < 2 {
+			return nil, fmt.Errorf("the maximum depth of random DAGs should be at least 2")
+		}
+	default:
+		return nil, fmt.Errorf("invalid DAG type: %s", g.dagType)
+	}
+	return g, nil
+}
+
+// utilizationParams returns the parameters of the utilization distribution
+func (g *Generator) utilizationParams() UtilizationParams {
+	return UtilizationParams{
+		NumTasks:    g.numTasks,
+		Utilization: g.utilization,
+		Bounds:      g.utilBound,
+		LowerBounds: g.utilLowerBounds,
+		UpperBounds: g.utilUpperBounds,
+	}
+}
+
+// jointPeriodParams returns the parameters of a joint distribution that is only used for the periods
+func (g *Generator) jointPeriodParams() UtilizationParams {
+	return UtilizationParams{
+		NumTasks:    g.numTasks,
+		Utilization: g.utilization,
+	}
+}
+
+// periodParams returns the parameters of the period distribution
+func (g *Generator) periodParams() PeriodParams {
+	return PeriodParams{
+		NumTasks:    g.numTasks,
+		Range:       g.periodRange,
+		Periods:     g.periods,
+		Base:        g.harmonicBase,
+		Multipliers: g.harmonicMult,
+		Chains:      g.harmonicChains,
+		Factors:     g.periodFactors,
+	}
+}
+
+// Seed returns the master seed of the generator
+func (g *Generator) Seed() int64 {
+	return g.seed
+}
+
+// Dir returns the folder of the task sets relative to the output folder, e.g.,
+// "uunifast-utilDist/uniform-perDist/4-core/5-task/10-percent-jitter/0.80-util"
+func (g *Generator) Dir() string {
+	dir := path.Join(fmt.Sprintf("%s-utilDist", g.utilDist), fmt.Sprintf("%s-perDist", g.periodDist),
+		fmt.Sprintf("%d-core", g.numCores), fmt.Sprintf("%d-task", g.numTasks))
+	if g.constantJitter {
+		dir = path.Join(dir, fmt.Sprintf("%d-jitter", int(g.jitter)))
+	} else {
+		dir = path.Join(dir, fmt.Sprintf("%d-percent-jitter", int(g.jitter*100)))
+	}
+	return path.Join(dir, fmt.Sprintf("%.2f-util", g.utilization))
+}
+
+// setKey returns the key of the task set with the given index, i.e., its path in the output folder
+// without the extension. The seeds of the set and its DAG are derived from this key.
+func (g *Generator) setKey(index int) string {
+	return path.Join(g.Dir(), "tasksets", fmt.Sprintf("%s_%d", g.periodDist, index))
+}
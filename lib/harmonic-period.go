@@ -0,0 +1,132 @@
+package lib
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// defaultHarmonicMultipliers are the multipliers between two successive harmonic periods without any given
+var defaultHarmonicMultipliers = []int{1, 2}
+
+// defaultHarmonicChains is the number of chains of the "k-harmonic-chains" distribution without any given
+const defaultHarmonicChains = 2
+
+// maxChainBaseDraws bounds the draws of the base of a chain that is not harmonic with the other chains
+const maxChainBaseDraws = 100
+
+// harmonicPeriodGenerator is the "harmonic" and the "k-harmonic-chains" period distribution. The periods of a chain
+// are harmonic: each period is the previous one times a multiplier drawn from the multipliers, as long as it stays in
+// the period range. The "harmonic" distribution has one chain from the base (the smallest period of the range by
+// default), and the "k-harmonic-chains" distribution spreads the tasks over chains whose bases are drawn
+// log-uniformly from the period range.
+type harmonicPeriodGenerator struct {
+	chains bool
+}
+
+// Check checks the period range, the base, the multipliers, and the number of chains
+func (h harmonicPeriodGenerator) Check(p PeriodParams) error {
+	if err := checkPeriodRange(p, false); err != nil {
+		return err
+	}
+	if h.chains && p.Base != 0 {
+		return fmt.Errorf("the harmonic base is only valid for the harmonic period distribution")
+	}
+	if !h.chains && p.Base != 0 && (p.Base < p.Range[0] || p.Base > p.Range[1]) {
+		return fmt.Errorf("the harmonic base %d is not in the period range [%d, %d]", p.Base, p.Range[0],
+			p.Range[1])
+	}
+	if !h.chains && p.Chains != 0 {
+		return fmt.Errorf("the number of harmonic chains is only valid for the k-harmonic-chains period distribution")
+	}
+	for _, m := range p.Multipliers {
+		if m < 1 {
+			return fmt.Errorf("the harmonic multipliers should be positive")
+		}
+	}
+	if p.Chains < 0 || p.Chains > p.NumTasks {
+		return fmt.Errorf("the number of harmonic chains should be between 1 and the number of tasks")
+	}
+	return nil
+}
+
+// Periods generates the harmonic chains and shuffles their periods over the tasks
+func (h harmonicPeriodGenerator) Periods(rng *rand.Rand, p PeriodParams) ([]int, error) {
+	multipliers := p.Multipliers
+	if len(multipliers) == 0 {
+		multipliers = defaultHarmonicMultipliers
+	}
+
+	var bases []int
+	if !h.chains {
+		base := p.Base
+		if base == 0 {
+			base = p.Range[0]
+		}
+		bases = []int{base}
+	} else {
+		numChains := p.Chains
+		if numChains == 0 {
+			numChains = min(defaultHarmonicChains, p.NumTasks)
+		}
+		bases = chainBases(rng, numChains, p.Range[0], p.Range[1])
+	}
+
+	// the tasks join the chains in turn
+	periods := make([]int, p.NumTasks)
+	last := append([]int(nil), bases...)
+	for i := range periods {
+		c := i % len(bases)
+		if i >= len(bases) {
+			last[c] = nextHarmonic(rng, last[c], p.Range[1], multipliers)
+		}
+		periods[i] = last[c]
+	}
+	rng.Shuffle(len(periods), func(i, j int) {
+		periods[i], periods[j] = periods[j], periods[i]
+	})
+	return periods, nil
+}
+
+// nextHarmonic returns the period times a multiplier drawn from the ones that keep it under the maximum period, or
+// the period itself if none does
+func nextHarmonic(rng *rand.Rand, period, maxPeriod int, multipliers []int) int {
+	var fit []int
+	for _, m := range multipliers {
+		if period*m <= maxPeriod {
+			fit = append(fit, m)
+		}
+	}
+	if len(fit) == 0 {
+		return period
+	}
+	return period * fit[rng.Intn(len(fit))]
+}
+
+// chainBases draws the bases of the chains log-uniformly from [minPeriod, maxPeriod]. A base that divides or is a
+// multiple of another one would merge two chains, so it is drawn again a few times.
+func chainBases(rng *rand.Rand, numChains, minPeriod, maxPeriod int) []int {
+	bases := make([]int, 0, numChains)
+	for len(bases) < numChains {
+		var base int
+		for draw := 0; draw < maxChainBaseDraws; draw++ {
+			base = int(math.Round(math.Exp(rng.Float64()*(math.Log(float64(maxPeriod))-math.Log(float64(minPeriod))) +
+				math.Log(float64(minPeriod)))))
+			if !harmonicWith(base, bases) {
+				break
+			}
+		}
+		bases = append(bases, base)
+	}
+	return bases
+}
+
+// harmonicWith reports whether the period divides or is a multiple of one of the periods
+func harmonicWith(period int, periods []int) bool {
+	for _, p := range periods {
+		if period%p == 0 || p%period == 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -6,11 +6,11 @@ import (
 )
 
 // generatePeriodsLogUniform generates log-uniformly distributed periods to create tasks.
-func generatePeriodsLogUniform(numTasks int, minPeriod, maxPeriod float64) []int {
+func generatePeriodsLogUniform(rng *rand.Rand, numTasks int, minPeriod, maxPeriod float64) []int {
 
 	periods := make([]int, numTasks)
 	for i := 0; i < numTasks; i++ {
-		periods[i] = int(math.Round(math.Exp(rand.Float64()*(math.Log(maxPeriod)-math.Log(minPeriod)) + math.Log(minPeriod))))
+		periods[i] = int(math.Round(math.Exp(rng.Float64()*(math.Log(maxPeriod)-math.Log(minPeriod)) + math.Log(minPeriod))))
 	}
 
 	return periods
@@ -18,8 +18,8 @@ func generatePeriodsLogUniform(numTasks int, minPeriod, maxPeriod float64) []int
 
 // generatePeriodsLogUniformDiscrete generates log-uniformly distributed periods and
 // rounds them down to the nearest predefined periods.
-func generatePeriodsLogUniformDiscrete(numTasks int, minPeriod, maxPeriod float64, roundDownSet []int) []int {
-	periodSet := generatePeriodsLogUniform(numTasks, minPeriod, maxPeriod)
+func generatePeriodsLogUniformDiscrete(rng *rand.Rand, numTasks int, minPeriod, maxPeriod float64, roundDownSet []int) []int {
+	periodSet := generatePeriodsLogUniform(rng, numTasks, minPeriod, maxPeriod)
 
 	roundedPeriodSets := make([]int, len(periodSet))
 	for i, p := range periodSet {
@@ -33,3 +33,22 @@ func generatePeriodsLogUniformDiscrete(numTasks int, minPeriod, maxPeriod float6
 
 	return roundedPeriodSets
 }
+
+// logUniformPeriodGenerator is the "log-uniform" and "log-uniform-discrete" period distribution
+type logUniformPeriodGenerator struct {
+	discrete bool
+}
+
+// Check checks the period range
+func (l logUniformPeriodGenerator) Check(p PeriodParams) error {
+	return checkPeriodRange(p, l.discrete)
+}
+
+// Periods generates log-uniformly distributed periods
+func (l logUniformPeriodGenerator) Periods(rng *rand.Rand, p PeriodParams) ([]int, error) {
+	if l.discrete {
+		return generatePeriodsLogUniformDiscrete(rng, p.NumTasks, float64(p.Range[0]), float64(p.Range[1]),
+			p.Periods), nil
+	}
+	return generatePeriodsLogUniform(rng, p.NumTasks, float64(p.Range[0]), float64(p.Range[1])), nil
+}
@@ -0,0 +1,156 @@
+package lib
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Version is the version of the generator that is recorded in the manifests
+const Version = "1.1.0"
+
+// manifestIndexFile is the name of the index file that lists all manifests of a task set folder
+const manifestIndexFile = "index.manifest.csv"
+
+// Provenance describes the run that generates the task sets
+type Provenance struct {
+	Config     interface{}
+	ConfigHash string
+}
+
+// NewProvenance takes a snapshot of the configuration and computes its hash. The keys of the fields that do not
+// change the generated sets (e.g., the output path) are left out of the hash, so the hash identifies the experiment.
+func NewProvenance(config interface{}, unhashedKeys ...string) (Provenance, error) {
+	snapshot, err := yaml.Marshal(config)
+	if err != nil {
+		return Provenance{}, err
+	}
+	var fields yaml.MapSlice
+	if err := yaml.Unmarshal(snapshot, &fields); err != nil {
+		return Provenance{}, err
+	}
+	hashed := make(yaml.MapSlice, 0, len(fields))
+	for _, field := range fields {
+		if key, ok := field.Key.(string); !ok || !slices.Contains(unhashedKeys, key) {
+			hashed = append(hashed, field)
+		}
+	}
+	if snapshot, err = yaml.Marshal(hashed); err != nil {
+		return Provenance{}, err
+	}
+	hash := sha256.Sum256(snapshot)
+	return Provenance{
+		Config:     config,
+		ConfigHash: hex.EncodeToString(hash[:]),
+	}, nil
+}
+
+// Manifest records how a task set is generated, so it can be traced back and regenerated
+type Manifest struct {
+	File             string      `yaml:"file"`
+	Key              string      `yaml:"key"`
+	Seed             int64       `yaml:"seed"`
+	MasterSeed       int64       `yaml:"master_seed"`
+	GeneratorVersion string      `yaml:"generator_version"`
+	ConfigHash       string      `yaml:"config_hash"`
+	Attempts         int         `yaml:"attempts"`
+	Rejected         int         `yaml:"rejected"`
+	Utilization      float64     `yaml:"utilization"`
+	Hyperperiod      int         `yaml:"hyperperiod"`
+	NumJobs          int         `yaml:"num_jobs"`
+	Cores            int         `yaml:"cores"`
+	Config           interface{} `yaml:"config"`
+}
+
+// manifestPath returns the path of the manifest of a task set, e.g., "uniform_0.manifest.yaml" for "uniform_0.csv"
+func manifestPath(taskSetPath string) string {
+	return taskSetPath[:strings.LastIndex(taskSetPath, ".")] + ".manifest.yaml"
+}
+
+// writeManifest writes the manifest of a task set next to it
+func writeManifest(taskSetPath string, manifest *Manifest) error {
+	data, err := yaml.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(manifestPath(taskSetPath), data, 0644)
+}
+
+// readManifest reads a manifest file
+func readManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var manifest Manifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+// writeManifestIndex collects all manifests in a task set folder and writes them to an index file
+func writeManifestIndex(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.manifest.yaml"))
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		// e.g., no set of the point passes the filter, so there is not even a folder
+		return nil
+	}
+
+	var manifests []*Manifest
+	for _, path := range paths {
+		manifest, err := readManifest(path)
+		if err != nil {
+			return fmt.Errorf("cannot read manifest %s: %v", path, err)
+		}
+		manifests = append(manifests, manifest)
+	}
+	// keep the index stable, no matter in which order the sets are generated
+	sort.Slice(manifests, func(i, j int) bool {
+		return manifests[i].File < manifests[j].File
+	})
+
+	file, err := os.Create(filepath.Join(dir, manifestIndexFile))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	headers := []string{"File", "Key", "Seed", "Master seed", "Generator version", "Config hash", "Attempts",
+		"Utilization", "Hyperperiod", "Jobs", "Rejected"}
+	writer.Write(headers)
+
+	for _, m := range manifests {
+		row := []string{
+			m.File,
+			m.Key,
+			strconv.FormatInt(m.Seed, 10),
+			strconv.FormatInt(m.MasterSeed, 10),
+			m.GeneratorVersion,
+			m.ConfigHash,
+			strconv.Itoa(m.Attempts),
+			strconv.FormatFloat(m.Utilization, 'f', 6, 64),
+			strconv.Itoa(m.Hyperperiod),
+			strconv.Itoa(m.NumJobs),
+			strconv.Itoa(m.Rejected),
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
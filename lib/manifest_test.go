@@ -0,0 +1,113 @@
+package lib
+
+import (
+	"encoding/csv"
+	"fmt"
+	"math"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testConfig is a configuration of the tests of the provenance
+type testConfig struct {
+	Path        string  `yaml:"path"`
+	Utilization float64 `yaml:"utilization"`
+	Verbose     int     `yaml:"verbose"`
+}
+
+func TestNewProvenance(t *testing.T) {
+	hash := func(config testConfig) string {
+		provenance, err := NewProvenance(config, "path", "verbose")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(provenance.Config, config) {
+			t.Errorf("the snapshot is %v instead of %v", provenance.Config, config)
+		}
+		return provenance.ConfigHash
+	}
+	base := hash(testConfig{Path: "out", Utilization: 0.8, Verbose: 1})
+	if len(base) != 64 {
+		t.Errorf("the hash %q is not a SHA-256 hash", base)
+	}
+	if hash(testConfig{Path: "other", Utilization: 0.8, Verbose: 4}) != base {
+		t.Error("the keys left out of the hash change it")
+	}
+	if hash(testConfig{Path: "out", Utilization: 0.9, Verbose: 1}) == base {
+		t.Error("the utilization does not change the hash")
+	}
+}
+
+func TestManifest(t *testing.T) {
+	provenance, err := NewProvenance(testConfig{Path: "out", Utilization: 0.8}, "path")
+	if err != nil {
+		t.Fatal(err)
+	}
+	options := []Option{
+		WithSeed(11),
+		WithCores(2),
+		WithTasks(4),
+		WithUtilization(0.8),
+		WithPeriodDistribution("uniform-discrete", []int{10, 100}, []int{10, 20, 50, 100}),
+		WithProvenance(provenance),
+	}
+	g, err := NewGenerator(options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := t.TempDir()
+	if err := g.WriteTaskSets(root, 3); err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(root, filepath.FromSlash(g.Dir()), "tasksets")
+
+	for index := 0; index < 3; index++ {
+		path := filepath.Join(dir, fmt.Sprintf("uniform-discrete_%d.csv", index))
+		manifest, err := readManifest(manifestPath(path))
+		if err != nil {
+			t.Fatal(err)
+		}
+		tasks, err := readTaskSet(path, "csv")
+		if err != nil {
+			t.Fatal(err)
+		}
+		key := g.setKey(index)
+		if manifest.File != filepath.Base(path) || manifest.Key != key || manifest.MasterSeed != 11 ||
+			manifest.Seed != deriveSeed(11, streamTaskSet, key) || manifest.GeneratorVersion != Version ||
+			manifest.ConfigHash != provenance.ConfigHash || manifest.Cores != 2 {
+			t.Errorf("manifest %+v", *manifest)
+		}
+		if math.Abs(manifest.Utilization-tasks.Utilization()) > 1e-9 || manifest.Hyperperiod != tasks.HyperPeriod() {
+			t.Errorf("the manifest has the utilization %f and the hyperperiod %d instead of %f and %d",
+				manifest.Utilization, manifest.Hyperperiod, tasks.Utilization(), tasks.HyperPeriod())
+		}
+
+		// the set is generated again from the seed of its manifest
+		regenerated, err := g.TaskSet(index)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, task := range regenerated {
+			if task.WCET != tasks[i].WCET || task.Period != tasks[i].Period || task.PE != tasks[i].PE {
+				t.Errorf("task %d of set %d is %+v instead of %+v", i, index, *task, *tasks[i])
+			}
+		}
+	}
+
+	// the index lists the manifests of the folder in order
+	files := readFiles(t, dir)
+	rows, err := csv.NewReader(strings.NewReader(files[manifestIndexFile])).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 4 {
+		t.Fatalf("%d rows in the index instead of 4", len(rows))
+	}
+	for i, row := range rows[1:] {
+		if want := fmt.Sprintf("uniform-discrete_%d.csv", i); row[0] != want {
+			t.Errorf("row %d lists %s instead of %s", i, row[0], want)
+		}
+	}
+}
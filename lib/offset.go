@@ -0,0 +1,38 @@
+package lib
+
+import (
+	"fmt"
+	"math/rand"
+	"task-generator/lib/common"
+)
+
+// checkOffsetModel checks the offset model and its granularity
+func checkOffsetModel(model string, granularity int) error {
+	switch model {
+	case "", "zero", "uniform":
+	case "granular":
+		if granularity < 1 {
+			return fmt.Errorf("the offset granularity should be positive")
+		}
+	default:
+		return fmt.Errorf("unknown offset model: %s", model)
+	}
+	return nil
+}
+
+// generateOffsets sets the release offset of each task with the offset model:
+// "zero" O = 0 (synchronous), "uniform" O in [0, T), and "granular" O a multiple of the granularity in [0, T)
+func generateOffsets(rng *rand.Rand, tasks common.TaskSet, model string, granularity int) {
+	for _, task := range tasks {
+		switch model {
+		case "uniform":
+			task.Offset = rng.Intn(task.Period)
+		case "granular":
+			// the number of multiples of the granularity below the period
+			steps := (task.Period + granularity - 1) / granularity
+			task.Offset = granularity * rng.Intn(steps)
+		default:
+			task.Offset = 0
+		}
+	}
+}
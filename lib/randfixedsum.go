@@ -1,6 +1,7 @@
 package lib
 
 import (
+	"fmt"
 	"math"
 	"math/rand"
 )
@@ -8,7 +9,7 @@ import (
 // StaffordRandFixedSum generates an n by m array x, each of whose m columns
 // contains n random values lying in the interval [a,b], but
 // subject to the condition that their sum be equal to s.
-func StaffordRandFixedSum(n int, u, a, b float64) []float64 {
+func StaffordRandFixedSum(rng *rand.Rand, n int, u, a, b float64) []float64 {
 
 	// Deal with n=1 case
 	if n == 1 {
@@ -64,8 +65,8 @@ func StaffordRandFixedSum(n int, u, a, b float64) []float64 {
 	for i := range x {
 		x[i] = 0.0
 	}
-	rt := rand.Float64() // rand simplex type
-	rs := rand.Float64() // rand position in simplex
+	rt := rng.Float64() // rand simplex type
+	rs := rng.Float64() // rand position in simplex
 	s = u
 	j := k + 1
 	sm := 0.0
@@ -98,3 +99,40 @@ func boolToFloat64(b bool) float64 {
 	}
 	return 0.0
 }
+
+// randFixedSumGenerator is the "rand-fixed-sum" utilization distribution
+type randFixedSumGenerator struct{}
+
+// bounds returns the [min, max] utilization of each task, by default [0, 1]
+func (randFixedSumGenerator) bounds(p UtilizationParams) (float64, float64) {
+	if len(p.Bounds) == 0 {
+		return 0.0, 1.0
+	}
+	return p.Bounds[0], p.Bounds[1]
+}
+
+// Check checks that the utilization can be reached with the bounds
+func (r randFixedSumGenerator) Check(p UtilizationParams) error {
+	if err := checkNoTaskBounds(p); err != nil {
+		return err
+	}
+	if len(p.Bounds) != 0 && len(p.Bounds) != 2 {
+		return fmt.Errorf("the utilization bounds should be given as [min, max]")
+	}
+	a, b := r.bounds(p)
+	if a < 0 || a >= b {
+		return fmt.Errorf("invalid utilization bounds [%.2f, %.2f]", a, b)
+	}
+	// a small tolerance, so a utilization exactly on the bounds is accepted despite floating-point errors
+	if p.Utilization < float64(p.NumTasks)*a-1e-9 || p.Utilization > float64(p.NumTasks)*b+1e-9 {
+		return fmt.Errorf("the utilization %.2f cannot be split into %d tasks in [%.2f, %.2f]", p.Utilization,
+			p.NumTasks, a, b)
+	}
+	return nil
+}
+
+// Utilizations generates the utilizations with RandFixedSum
+func (r randFixedSumGenerator) Utilizations(rng *rand.Rand, p UtilizationParams) ([]float64, error) {
+	a, b := r.bounds(p)
+	return StaffordRandFixedSum(rng, p.NumTasks, p.Utilization, a, b), nil
+}
@@ -0,0 +1,48 @@
+package lib
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"path/filepath"
+	"strings"
+)
+
+// names of the random streams derived from the master seed
+const (
+	streamTaskSet = "taskset"
+	streamDAG     = "dag"
+	streamJobSet  = "jobset"
+)
+
+// deriveSeed derives the seed of an independent random stream from the master seed.
+// The stream names the kind of artifact (task set, DAG, ...) and the key identifies the artifact itself,
+// so the same artifact always gets the same seed, no matter in which order (or in parallel) it is generated.
+func deriveSeed(master int64, stream, key string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(stream))
+	h.Write([]byte{0})
+	h.Write([]byte(key))
+
+	// splitmix64 finalizer to spread the bits of close master seeds
+	z := uint64(master) ^ h.Sum64()
+	z += 0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return int64(z ^ (z >> 31))
+}
+
+// newRand creates a random generator for the given stream and key
+func newRand(master int64, stream, key string) *rand.Rand {
+	return rand.New(rand.NewSource(deriveSeed(master, stream, key)))
+}
+
+// seedKey returns the key of a generated file, i.e., its path relative to the output folder without the extension.
+// The key does not depend on the output format, so CSV and YAML outputs of the same seed contain the same sets.
+func seedKey(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		rel = path
+	}
+	rel = filepath.ToSlash(rel)
+	return strings.TrimSuffix(rel, filepath.Ext(rel))
+}
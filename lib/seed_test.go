@@ -0,0 +1,121 @@
+package lib
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// readFiles returns the contents of the files in a folder, by their paths relative to the folder
+func readFiles(t *testing.T, root string) map[string]string {
+	t.Helper()
+	files := make(map[string]string)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(root, path)
+		files[filepath.ToSlash(rel)] = string(data)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+// generate writes the task sets, their DAGs, and their job sets of a generator to a temporary folder, and returns the
+// contents of the written files
+func generate(t *testing.T, numSets int, options ...Option) map[string]string {
+	t.Helper()
+	g, err := NewGenerator(options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := t.TempDir()
+	if err := g.WriteTaskSets(root, numSets); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.WriteDAGSets(root, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.WriteJobSets(root); err != nil {
+		t.Fatal(err)
+	}
+	return readFiles(t, root)
+}
+
+func TestReproducibleOutput(t *testing.T) {
+	options := func(seed int64, parallel bool) []Option {
+		return []Option{
+			WithSeed(seed),
+			WithCores(2),
+			WithTasks(5),
+			WithUtilization(1.2),
+			WithPeriodDistribution("uniform-discrete", []int{10, 100}, []int{10, 20, 40, 50, 100}),
+			WithJitter(0.1, false),
+			WithExecVariation(0.5),
+			WithDeadlines("constrained", 0),
+			WithMappingHeuristic(1),
+			WithForkJoinDAG(0.5, 0.5, 3, 6, 3),
+			WithArrivals("uniform", 0.2, 0),
+			WithParallel(parallel),
+		}
+	}
+	sequential := generate(t, 8, options(7, false)...)
+	kinds := map[string]int{}
+	for path := range sequential {
+		for _, kind := range []string{"tasksets/", ".prec.", "jobsets/", ".manifest."} {
+			if strings.Contains(path, kind) {
+				kinds[kind]++
+			}
+		}
+	}
+	if len(kinds) != 4 {
+		t.Fatalf("the task sets, the DAGs, the job sets, and the manifests are not all written: %v", kinds)
+	}
+	for _, parallel := range []bool{false, true} {
+		if files := generate(t, 8, options(7, parallel)...); !reflect.DeepEqual(files, sequential) {
+			t.Errorf("the output of the same seed differs (parallel: %v)", parallel)
+		}
+	}
+
+	// the sets of another seed are different, but at the same paths
+	other := generate(t, 8, options(8, true)...)
+	if len(other) != len(sequential) {
+		t.Fatalf("%d files instead of %d", len(other), len(sequential))
+	}
+	for path, content := range other {
+		if _, ok := sequential[path]; !ok {
+			t.Errorf("unexpected file %s", path)
+		} else if content == sequential[path] && filepath.Ext(path) == ".csv" {
+			t.Errorf("%s is the same for another seed", path)
+		}
+	}
+}
+
+func TestDeriveSeed(t *testing.T) {
+	// the seeds of the streams and the keys are independent of each other
+	seeds := make(map[int64]bool)
+	for _, master := range []int64{0, 1, 2} {
+		for _, stream := range []string{streamTaskSet, streamDAG, streamJobSet} {
+			for _, key := range []string{"a/tasksets/uniform_0", "a/tasksets/uniform_1"} {
+				seed := deriveSeed(master, stream, key)
+				if seed != deriveSeed(master, stream, key) {
+					t.Fatalf("the seed of %s %s is not deterministic", stream, key)
+				}
+				seeds[seed] = true
+			}
+		}
+	}
+	if len(seeds) != 18 {
+		t.Errorf("%d different seeds instead of 18", len(seeds))
+	}
+}
@@ -0,0 +1,169 @@
+package sim
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// the layout of the Gantt chart, in pixels
+const (
+	ganttWidth     = 1200
+	ganttLeft      = 80
+	ganttTop       = 40
+	ganttRowHeight = 32
+	ganttBoxHeight = 18
+	ganttBottom    = 40
+)
+
+// ganttColors are the colors of the tasks in the Gantt chart
+var ganttColors = []string{"#4e79a7", "#f28e2b", "#59a14f", "#b07aa1", "#76b7b2", "#edc948", "#9c755f", "#bab0ac",
+	"#ff9da7", "#86bcb6"}
+
+// GanttSVG renders the schedule in the window [from, to) as an SVG Gantt chart with a row for each task. The boxes
+// are the intervals in which the jobs run, labeled with their core, and the jobs have an arrow up at their release and
+// an arrow down at their deadline, which is red if the deadline is missed. Without an end of the window (to <= from),
+// the whole schedule is rendered.
+func (r Result) GanttSVG(from, to int) string {
+	if to <= from {
+		to = r.Makespan
+		for _, job := range r.Jobs {
+			to = max(to, job.Deadline)
+		}
+		to = max(to, from+1)
+	}
+	scale := float64(ganttWidth) / float64(to-from)
+	x := func(t int) float64 {
+		return ganttLeft + float64(t-from)*scale
+	}
+	rows := make(map[int]int, len(r.Tasks))
+	for row, task := range r.Tasks {
+		rows[task.TaskID] = row
+	}
+	y := func(taskID int) float64 {
+		return float64(ganttTop + rows[taskID]*ganttRowHeight)
+	}
+	height := ganttTop + len(r.Tasks)*ganttRowHeight + ganttBottom
+
+	var svg strings.Builder
+	fmt.Fprintf(&svg, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" font-family=\"sans-serif\" "+
+		"font-size=\"11\">\n", ganttLeft+ganttWidth+20, height)
+	scheduling, preemption := "global", "non-preemptive"
+	if r.Partitioned {
+		scheduling = "partitioned"
+	}
+	if r.Preemptive {
+		preemption = "preemptive"
+	}
+	fmt.Fprintf(&svg, "<text x=\"%d\" y=\"20\" font-size=\"14\">%d cores, %s, %s: %d deadline misses</text>\n",
+		ganttLeft, r.Cores, scheduling, preemption, r.Misses)
+
+	// the rows of the tasks
+	for row, task := range r.Tasks {
+		top := float64(ganttTop + row*ganttRowHeight)
+		fmt.Fprintf(&svg, "<text x=\"4\" y=\"%.1f\">Task %d</text>\n", top+ganttBoxHeight-4, task.TaskID)
+		fmt.Fprintf(&svg, "<line x1=\"%d\" y1=\"%.1f\" x2=\"%d\" y2=\"%.1f\" stroke=\"#ddd\"/>\n", ganttLeft,
+			top+ganttBoxHeight, ganttLeft+ganttWidth, top+ganttBoxHeight)
+	}
+
+	// the time axis
+	axis := float64(ganttTop + len(r.Tasks)*ganttRowHeight)
+	fmt.Fprintf(&svg, "<line x1=\"%d\" y1=\"%.1f\" x2=\"%d\" y2=\"%.1f\" stroke=\"black\"/>\n", ganttLeft, axis,
+		ganttLeft+ganttWidth, axis)
+	step := tickStep(to - from)
+	for t := (from + step - 1) / step * step; t <= to; t += step {
+		fmt.Fprintf(&svg, "<line x1=\"%.1f\" y1=\"%.1f\" x2=\"%.1f\" y2=\"%.1f\" stroke=\"black\"/>\n", x(t), axis,
+			x(t), axis+5)
+		fmt.Fprintf(&svg, "<text x=\"%.1f\" y=\"%.1f\" text-anchor=\"middle\">%d</text>\n", x(t), axis+18, t)
+	}
+
+	// the intervals in which the jobs run
+	for _, interval := range r.Intervals() {
+		start, end := max(interval.Start, from), min(interval.End, to)
+		if start >= end {
+			continue
+		}
+		color := ganttColors[rows[interval.TaskID]%len(ganttColors)]
+		fmt.Fprintf(&svg, "<rect x=\"%.1f\" y=\"%.1f\" width=\"%.1f\" height=\"%d\" fill=\"%s\" stroke=\"black\" "+
+			"stroke-width=\"0.5\"><title>task %d, job %d, core %d: [%d, %d)</title></rect>\n", x(start),
+			y(interval.TaskID), float64(end-start)*scale, ganttBoxHeight, color, interval.TaskID, interval.JobID,
+			interval.Core, interval.Start, interval.End)
+		if float64(end-start)*scale >= 14 {
+			fmt.Fprintf(&svg, "<text x=\"%.1f\" y=\"%.1f\" text-anchor=\"middle\" fill=\"white\">%d</text>\n",
+				(x(start)+x(end))/2, y(interval.TaskID)+ganttBoxHeight-5, interval.Core)
+		}
+	}
+
+	// the releases and the deadlines of the jobs
+	for _, job := range r.Jobs {
+		top := y(job.TaskID)
+		if job.Release >= from && job.Release <= to {
+			fmt.Fprintf(&svg, "<path d=\"M%.1f %.1f V%.1f M%.1f %.1f L%.1f %.1f L%.1f %.1f\" stroke=\"black\" "+
+				"fill=\"none\"><title>job %d released at %d</title></path>\n", x(job.Release), top+ganttBoxHeight,
+				top-6, x(job.Release)-3, top-2, x(job.Release), top-6, x(job.Release)+3, top-2, job.JobID, job.Release)
+		}
+		if job.Deadline >= from && job.Deadline <= to {
+			color := "#555"
+			if job.Missed {
+				color = "red"
+			}
+			fmt.Fprintf(&svg, "<path d=\"M%.1f %.1f V%.1f M%.1f %.1f L%.1f %.1f L%.1f %.1f\" stroke=\"%s\" "+
+				"fill=\"none\"><title>job %d due at %d</title></path>\n", x(job.Deadline), top-6,
+				top+ganttBoxHeight, x(job.Deadline)-3, top+ganttBoxHeight-4, x(job.Deadline), top+ganttBoxHeight,
+				x(job.Deadline)+3, top+ganttBoxHeight-4, color, job.JobID, job.Deadline)
+		}
+	}
+	svg.WriteString("</svg>\n")
+	return svg.String()
+}
+
+// tickStep returns a round distance between the ticks of a time axis of the given length, for about 10 ticks
+func tickStep(length int) int {
+	step := int(math.Pow(10, math.Floor(math.Log10(float64(max(length, 1))/10))))
+	step = max(step, 1)
+	for length/step > 10 {
+		switch {
+		case length/(2*step) <= 10:
+			step *= 2
+		case length/(5*step) <= 10:
+			step *= 5
+		default:
+			step *= 10
+		}
+	}
+	return step
+}
+
+// ganttHTML wraps the Gantt chart in a standalone HTML page with the missed deadlines
+func (r Result) ganttHTML(from, to int) string {
+	var html strings.Builder
+	html.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>Schedule</title>\n" +
+		"<style>body { font-family: sans-serif; } td, th { padding: 2px 8px; text-align: right; }</style>\n" +
+		"</head>\n<body>\n<h1>Schedule</h1>\n")
+	html.WriteString(r.GanttSVG(from, to))
+	if r.Misses > 0 {
+		fmt.Fprintf(&html, "<h2>%d deadline misses</h2>\n<table>\n<tr><th>Task</th><th>Job</th><th>Release</th>"+
+			"<th>Deadline</th><th>Finish</th><th>Core</th></tr>\n", r.Misses)
+		for _, job := range r.Jobs {
+			if job.Missed {
+				fmt.Fprintf(&html, "<tr><td>%d</td><td>%d</td><td>%d</td><td>%d</td><td>%d</td><td>%d</td></tr>\n",
+					job.TaskID, job.JobID, job.Release, job.Deadline, job.Finish, job.Core)
+			}
+		}
+		html.WriteString("</table>\n")
+	}
+	html.WriteString("</body>\n</html>\n")
+	return html.String()
+}
+
+// WriteGantt writes the Gantt chart of the schedule in the window [from, to) (see GanttSVG) to an SVG file, or to
+// a standalone HTML page with a list of the missed deadlines if the path ends with ".html"
+func (r Result) WriteGantt(path string, from, to int) error {
+	content := r.GanttSVG(from, to)
+	if strings.EqualFold(filepath.Ext(path), ".html") {
+		content = r.ganttHTML(from, to)
+	}
+	return os.WriteFile(path, []byte(content), 0644)
+}
@@ -0,0 +1,377 @@
+package sim
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"task-generator/lib/common"
+)
+
+// the kinds of the events of the trace
+const (
+	Release = "release"
+	Start   = "start"
+	Preempt = "preempt"
+	Resume  = "resume"
+	Finish  = "finish"
+	Miss    = "deadline-miss"
+)
+
+// Event is a change in the schedule. The core of the release and the deadline miss events is -1.
+type Event struct {
+	Time   int    `yaml:"time" json:"time"`
+	Kind   string `yaml:"kind" json:"kind"`
+	Core   int    `yaml:"core" json:"core"`
+	TaskID int    `yaml:"task_id" json:"task_id"`
+	JobID  int    `yaml:"job_id" json:"job_id"`
+}
+
+// JobResult is the schedule of a job. The response time is measured from the earliest arrival time of the job, like
+// in the analysis with release jitter. The start, the finish, and the response time of a job that never finishes
+// are -1.
+type JobResult struct {
+	TaskID       int  `yaml:"task_id"`
+	JobID        int  `yaml:"job_id"`
+	Core         int  `yaml:"core"`
+	Release      int  `yaml:"release"`
+	Execution    int  `yaml:"execution"`
+	Deadline     int  `yaml:"deadline"`
+	Start        int  `yaml:"start"`
+	Finish       int  `yaml:"finish"`
+	ResponseTime int  `yaml:"response_time"`
+	Missed       bool `yaml:"missed"`
+}
+
+// TaskResult sums up the jobs of a task
+type TaskResult struct {
+	TaskID          int `yaml:"task_id"`
+	Jobs            int `yaml:"jobs"`
+	Misses          int `yaml:"misses"`
+	MaxResponseTime int `yaml:"max_response_time"`
+}
+
+// Result is the schedule of a job set. It is deadlocked if some jobs wait for each other in a cycle of precedence.
+type Result struct {
+	Cores       int          `yaml:"cores"`
+	Partitioned bool         `yaml:"partitioned"`
+	Preemptive  bool         `yaml:"preemptive"`
+	Misses      int          `yaml:"misses"`
+	Schedulable bool         `yaml:"schedulable"`
+	Deadlock    bool         `yaml:"deadlock"`
+	Makespan    int          `yaml:"makespan"`
+	Tasks       []TaskResult `yaml:"tasks"`
+	Jobs        []JobResult  `yaml:"jobs"`
+	Events      []Event      `yaml:"events"`
+}
+
+// jobState is the state of a job in the simulation
+type jobState struct {
+	release   int
+	execution int
+	remaining int
+	pe        int
+	// onCore is the core that runs the job, or -1
+	onCore   int
+	lastCore int
+	start    int
+	finish   int
+	waiting  int
+	released bool
+}
+
+// engine is a running simulation
+type engine struct {
+	*Simulator
+	jobs    common.JobSet
+	state   []jobState
+	running []int
+	ready   []int
+	events  []Event
+}
+
+// Run simulates the job set and returns its schedule. A ready job has been released and all its predecessors
+// (see common.JobSet.Dependencies) have finished. The jobs with the same priority are ordered by their release and
+// then by their index in the job set.
+func (s *Simulator) Run(jobs common.JobSet) (Result, error) {
+	rng := rand.New(rand.NewSource(s.seed))
+	e := &engine{Simulator: s, jobs: jobs, state: make([]jobState, len(jobs)), running: make([]int, s.cores)}
+	for i := range e.running {
+		e.running[i] = -1
+	}
+	for i, job := range jobs {
+		bcet, wcet := job.Costs()
+		st := &e.state[i]
+		st.release = sample(rng, s.release, job.EarliestArrivalTime, job.LatestArrivalTime)
+		st.execution = sample(rng, s.execution, bcet, wcet)
+		st.remaining = st.execution
+		st.onCore, st.lastCore, st.start, st.finish = -1, -1, -1, -1
+		if s.partitioned {
+			st.pe = job.PE()
+			if st.pe < 0 || st.pe >= s.cores {
+				return Result{}, fmt.Errorf("job %d of task %d is mapped to core %d of %d cores", job.JobID,
+					job.TaskID, st.pe, s.cores)
+			}
+		}
+	}
+	successors := jobs.Dependencies()
+	for _, jobSuccessors := range successors {
+		for _, j := range jobSuccessors {
+			e.state[j].waiting++
+		}
+	}
+
+	order := make([]int, len(jobs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return e.state[order[a]].release < e.state[order[b]].release
+	})
+
+	t, next, finished := 0, 0, 0
+	if len(order) > 0 {
+		t = e.state[order[0]].release
+	}
+	for finished < len(jobs) {
+		for ; next < len(order) && e.state[order[next]].release <= t; next++ {
+			i := order[next]
+			e.state[i].released = true
+			e.event(t, Release, -1, i)
+			if e.state[i].waiting == 0 {
+				e.ready = append(e.ready, i)
+			}
+		}
+
+		// the jobs without execution time left finish, which can make other jobs ready at the same time
+		for {
+			done := false
+			for core, i := range e.running {
+				if i != -1 && e.state[i].remaining == 0 {
+					e.finish(t, core, successors[i])
+					finished++
+					done = true
+				}
+			}
+			if !done {
+				e.dispatch(t)
+				done = true
+				for _, i := range e.running {
+					if i != -1 && e.state[i].remaining == 0 {
+						done = false
+					}
+				}
+				if done {
+					break
+				}
+			}
+		}
+		if finished == len(jobs) {
+			break
+		}
+
+		// the next event is a finish or a release
+		step := math.MaxInt
+		for _, i := range e.running {
+			if i != -1 {
+				step = min(step, e.state[i].remaining)
+			}
+		}
+		if next < len(order) {
+			step = min(step, e.state[order[next]].release-t)
+		}
+		if step == math.MaxInt {
+			// nothing runs and nothing is released, so the other jobs wait for each other
+			break
+		}
+		for _, i := range e.running {
+			if i != -1 {
+				e.state[i].remaining -= step
+			}
+		}
+		t += step
+	}
+	return e.result(finished < len(jobs)), nil
+}
+
+// event adds an event of a job to the trace
+func (e *engine) event(t int, kind string, core, i int) {
+	e.events = append(e.events, Event{Time: t, Kind: kind, Core: core, TaskID: e.jobs[i].TaskID,
+		JobID: e.jobs[i].JobID})
+}
+
+// finish ends the job on a core, and makes its successors ready when it is their last predecessor
+func (e *engine) finish(t, core int, successors []int) {
+	i := e.running[core]
+	e.running[core] = -1
+	e.state[i].onCore = -1
+	e.state[i].finish = t
+	e.event(t, Finish, core, i)
+	for k, j := range e.ready {
+		if j == i {
+			e.ready = append(e.ready[:k], e.ready[k+1:]...)
+			break
+		}
+	}
+	for _, j := range successors {
+		e.state[j].waiting--
+		if e.state[j].waiting == 0 && e.state[j].released {
+			e.ready = append(e.ready, j)
+		}
+	}
+}
+
+// dispatch chooses the jobs that run on the cores
+func (e *engine) dispatch(t int) {
+	if !e.partitioned {
+		cores := make([]int, e.cores)
+		for c := range cores {
+			cores[c] = c
+		}
+		e.schedule(t, cores, e.ready)
+		return
+	}
+	for c := 0; c < e.cores; c++ {
+		var candidates []int
+		for _, i := range e.ready {
+			if e.state[i].pe == c {
+				candidates = append(candidates, i)
+			}
+		}
+		e.schedule(t, []int{c}, candidates)
+	}
+}
+
+// schedule chooses the jobs of the candidates that run on the cores, where the candidates are the ready jobs that
+// can run on these cores
+func (e *engine) schedule(t int, cores []int, candidates []int) {
+	candidates = append([]int(nil), candidates...)
+	sort.SliceStable(candidates, func(a, b int) bool {
+		i, j := candidates[a], candidates[b]
+		if e.jobs[i].Priority != e.jobs[j].Priority {
+			return e.jobs[i].Priority < e.jobs[j].Priority
+		}
+		if e.state[i].release != e.state[j].release {
+			return e.state[i].release < e.state[j].release
+		}
+		return i < j
+	})
+
+	var selected []int
+	if e.preemptive {
+		selected = candidates[:min(len(cores), len(candidates))]
+	} else {
+		// the running jobs keep their cores, and the free cores take the next jobs
+		free := 0
+		for _, c := range cores {
+			if e.running[c] == -1 {
+				free++
+			} else {
+				selected = append(selected, e.running[c])
+			}
+		}
+		for _, i := range candidates {
+			if free == 0 {
+				break
+			}
+			if e.state[i].onCore == -1 {
+				selected = append(selected, i)
+				free--
+			}
+		}
+	}
+
+	isSelected := make(map[int]bool, len(selected))
+	for _, i := range selected {
+		isSelected[i] = true
+	}
+	for _, c := range cores {
+		if i := e.running[c]; i != -1 && !isSelected[i] {
+			e.running[c] = -1
+			e.state[i].onCore = -1
+			e.event(t, Preempt, c, i)
+		}
+	}
+	// a job that keeps running stays on its core
+	for _, i := range selected {
+		if e.state[i].onCore != -1 {
+			continue
+		}
+		for _, c := range cores {
+			if e.running[c] == -1 {
+				e.running[c] = i
+				st := &e.state[i]
+				st.onCore, st.lastCore = c, c
+				if st.start == -1 {
+					st.start = t
+					e.event(t, Start, c, i)
+				} else {
+					e.event(t, Resume, c, i)
+				}
+				break
+			}
+		}
+	}
+}
+
+// result collects the schedule of the jobs and their tasks
+func (e *engine) result(deadlock bool) Result {
+	result := Result{
+		Cores:       e.cores,
+		Partitioned: e.partitioned,
+		Preemptive:  e.preemptive,
+		Deadlock:    deadlock,
+	}
+	tasks := make(map[int]*TaskResult)
+	var taskIDs []int
+	for i, job := range e.jobs {
+		st := e.state[i]
+		responseTime := -1
+		if st.finish != -1 {
+			responseTime = st.finish - job.EarliestArrivalTime
+			result.Makespan = max(result.Makespan, st.finish)
+		}
+		missed := st.finish == -1 || st.finish > job.AbsoluteDeadline
+		if missed {
+			result.Misses++
+			e.events = append(e.events, Event{Time: job.AbsoluteDeadline, Kind: Miss, Core: -1, TaskID: job.TaskID,
+				JobID: job.JobID})
+		}
+		result.Jobs = append(result.Jobs, JobResult{
+			TaskID:       job.TaskID,
+			JobID:        job.JobID,
+			Core:         st.lastCore,
+			Release:      st.release,
+			Execution:    st.execution,
+			Deadline:     job.AbsoluteDeadline,
+			Start:        st.start,
+			Finish:       st.finish,
+			ResponseTime: responseTime,
+			Missed:       missed,
+		})
+
+		task, ok := tasks[job.TaskID]
+		if !ok {
+			task = &TaskResult{TaskID: job.TaskID}
+			tasks[job.TaskID] = task
+			taskIDs = append(taskIDs, job.TaskID)
+		}
+		task.Jobs++
+		if missed {
+			task.Misses++
+		}
+		task.MaxResponseTime = max(task.MaxResponseTime, responseTime)
+	}
+	sort.Ints(taskIDs)
+	for _, id := range taskIDs {
+		result.Tasks = append(result.Tasks, *tasks[id])
+	}
+	result.Schedulable = result.Misses == 0
+
+	// the deadline misses go after the other events at the same time
+	sort.SliceStable(e.events, func(a, b int) bool {
+		return e.events[a].Time < e.events[b].Time
+	})
+	result.Events = e.events
+	return result
+}
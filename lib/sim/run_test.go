@@ -0,0 +1,249 @@
+package sim
+
+import (
+	"reflect"
+	"task-generator/lib/common"
+	"testing"
+)
+
+// job creates a job with a fixed release and execution time on a core, whose task ID is its name
+func job(taskID, jobID, release, cost, deadline, priority, pe int) *common.Job {
+	return &common.Job{
+		Task:                &common.Task{TaskID: taskID, BCET: cost, WCET: cost, PE: pe},
+		TaskID:              taskID,
+		JobID:               jobID,
+		EarliestArrivalTime: release,
+		LatestArrivalTime:   release,
+		AbsoluteDeadline:    deadline,
+		Priority:            priority,
+	}
+}
+
+func TestRun(t *testing.T) {
+	// the intervals are in the order of their start, and then of their end
+	tests := []struct {
+		name        string
+		jobs        common.JobSet
+		cores       int
+		partitioned bool
+		preemptive  bool
+		intervals   []Interval
+		misses      int
+		makespan    int
+	}{
+		{
+			// the second job of task 0 preempts task 1 at 4
+			name:       "preemptive",
+			jobs:       common.JobSet{job(0, 0, 0, 1, 4, 1, 0), job(0, 1, 4, 1, 8, 1, 0), job(1, 0, 0, 4, 10, 2, 0)},
+			cores:      1,
+			preemptive: true,
+			intervals: []Interval{
+				{TaskID: 0, JobID: 0, Core: 0, Start: 0, End: 1},
+				{TaskID: 1, JobID: 0, Core: 0, Start: 1, End: 4},
+				{TaskID: 0, JobID: 1, Core: 0, Start: 4, End: 5},
+				{TaskID: 1, JobID: 0, Core: 0, Start: 5, End: 6},
+			},
+			makespan: 6,
+		},
+		{
+			// the second job of task 0 waits for task 1 to finish
+			name:  "non-preemptive",
+			jobs:  common.JobSet{job(0, 0, 0, 1, 4, 1, 0), job(0, 1, 4, 1, 8, 1, 0), job(1, 0, 0, 4, 10, 2, 0)},
+			cores: 1,
+			intervals: []Interval{
+				{TaskID: 0, JobID: 0, Core: 0, Start: 0, End: 1},
+				{TaskID: 1, JobID: 0, Core: 0, Start: 1, End: 5},
+				{TaskID: 0, JobID: 1, Core: 0, Start: 5, End: 6},
+			},
+			makespan: 6,
+		},
+		{
+			// the blocking of the lower priority job makes the job of task 0 miss its deadline at 3
+			name:  "non-preemptive blocking",
+			jobs:  common.JobSet{job(0, 0, 1, 1, 3, 1, 0), job(1, 0, 0, 4, 10, 2, 0)},
+			cores: 1,
+			intervals: []Interval{
+				{TaskID: 1, JobID: 0, Core: 0, Start: 0, End: 4},
+				{TaskID: 0, JobID: 0, Core: 0, Start: 4, End: 5},
+			},
+			misses:   1,
+			makespan: 5,
+		},
+		{
+			// task 3 preempts the lowest priority running job, which resumes on the other core at 2
+			name: "global",
+			jobs: common.JobSet{job(1, 0, 0, 2, 10, 1, 0), job(2, 0, 0, 3, 10, 2, 0), job(3, 0, 0, 2, 10, 3, 0),
+				job(0, 0, 1, 1, 10, 0, 0)},
+			cores:      2,
+			preemptive: true,
+			intervals: []Interval{
+				{TaskID: 2, JobID: 0, Core: 1, Start: 0, End: 1},
+				{TaskID: 1, JobID: 0, Core: 0, Start: 0, End: 2},
+				{TaskID: 0, JobID: 0, Core: 1, Start: 1, End: 2},
+				{TaskID: 2, JobID: 0, Core: 0, Start: 2, End: 4},
+				{TaskID: 3, JobID: 0, Core: 1, Start: 2, End: 4},
+			},
+			makespan: 4,
+		},
+		{
+			// the same jobs, where tasks 1 and 2 run on core 0 and tasks 3 and 0 on core 1
+			name: "partitioned",
+			jobs: common.JobSet{job(1, 0, 0, 2, 10, 1, 0), job(2, 0, 0, 3, 10, 2, 0), job(3, 0, 0, 2, 10, 3, 1),
+				job(0, 0, 1, 1, 10, 0, 1)},
+			cores:       2,
+			partitioned: true,
+			preemptive:  true,
+			intervals: []Interval{
+				{TaskID: 3, JobID: 0, Core: 1, Start: 0, End: 1},
+				{TaskID: 1, JobID: 0, Core: 0, Start: 0, End: 2},
+				{TaskID: 0, JobID: 0, Core: 1, Start: 1, End: 2},
+				{TaskID: 3, JobID: 0, Core: 1, Start: 2, End: 3},
+				{TaskID: 2, JobID: 0, Core: 0, Start: 2, End: 5},
+			},
+			makespan: 5,
+		},
+		{
+			// the non-preemptive partitioned jobs only wait for the jobs of their own core
+			name: "partitioned non-preemptive",
+			jobs: common.JobSet{job(1, 0, 0, 2, 10, 1, 0), job(2, 0, 0, 3, 10, 2, 0), job(3, 0, 0, 2, 10, 3, 1),
+				job(0, 0, 1, 1, 10, 0, 1)},
+			cores:       2,
+			partitioned: true,
+			intervals: []Interval{
+				{TaskID: 1, JobID: 0, Core: 0, Start: 0, End: 2},
+				{TaskID: 3, JobID: 0, Core: 1, Start: 0, End: 2},
+				{TaskID: 0, JobID: 0, Core: 1, Start: 2, End: 3},
+				{TaskID: 2, JobID: 0, Core: 0, Start: 2, End: 5},
+			},
+			makespan: 5,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s, err := New(WithCores(test.cores), WithPartitioned(test.partitioned), WithPreemptive(test.preemptive))
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := s.Run(test.jobs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if intervals := result.Intervals(); !reflect.DeepEqual(intervals, test.intervals) {
+				t.Errorf("intervals = %+v, want %+v", intervals, test.intervals)
+			}
+			if result.Misses != test.misses || result.Schedulable != (test.misses == 0) {
+				t.Errorf("%d misses, schedulable = %v, want %d misses", result.Misses, result.Schedulable,
+					test.misses)
+			}
+			if result.Makespan != test.makespan {
+				t.Errorf("makespan = %d, want %d", result.Makespan, test.makespan)
+			}
+			if result.Deadlock {
+				t.Error("deadlock")
+			}
+		})
+	}
+}
+
+func TestRunDependencies(t *testing.T) {
+	// the job of the highest priority waits for its predecessor, although a core is free
+	jobs := common.JobSet{job(0, 0, 0, 2, 10, 2, 0), job(1, 0, 0, 1, 10, 1, 0)}
+	jobs[0].Successors = []int{1}
+	s, err := New(WithCores(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := s.Run(jobs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Interval{
+		{TaskID: 0, JobID: 0, Core: 0, Start: 0, End: 2},
+		{TaskID: 1, JobID: 0, Core: 0, Start: 2, End: 3},
+	}
+	if intervals := result.Intervals(); !reflect.DeepEqual(intervals, want) {
+		t.Errorf("intervals = %+v, want %+v", intervals, want)
+	}
+	if response := result.Jobs[1].ResponseTime; response != 3 {
+		t.Errorf("response time = %d, want 3", response)
+	}
+
+	// the jobs that wait for each other never run
+	jobs[1].Successors = []int{0}
+	result, err = s.Run(jobs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Deadlock || result.Misses != 2 || result.Jobs[0].Finish != -1 {
+		t.Errorf("deadlock = %v with %d misses, want a deadlock with 2 misses", result.Deadlock, result.Misses)
+	}
+}
+
+func TestRunSampling(t *testing.T) {
+	newJob := func() common.JobSet {
+		j := job(0, 0, 1, 6, 20, 1, 0)
+		j.LatestArrivalTime = 3
+		j.Task.BCET = 2
+		return common.JobSet{j}
+	}
+	tests := []struct {
+		policy             string
+		release, execution int
+	}{
+		{WCET, 3, 6},
+		{BCET, 1, 2},
+	}
+	for _, test := range tests {
+		t.Run(test.policy, func(t *testing.T) {
+			s, err := New(WithExecution(test.policy), WithRelease(test.policy))
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := s.Run(newJob())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if j := result.Jobs[0]; j.Release != test.release || j.Execution != test.execution {
+				t.Errorf("release %d and execution %d, want %d and %d", j.Release, j.Execution, test.release,
+					test.execution)
+			}
+		})
+	}
+
+	// the uniform samples are within the intervals and repeat with the same seed
+	for seed := int64(0); seed < 20; seed++ {
+		s, err := New(WithExecution(Uniform), WithRelease(Uniform), WithSeed(seed))
+		if err != nil {
+			t.Fatal(err)
+		}
+		first, _ := s.Run(newJob())
+		second, _ := s.Run(newJob())
+		j := first.Jobs[0]
+		if j.Release < 1 || j.Release > 3 || j.Execution < 2 || j.Execution > 6 {
+			t.Fatalf("release %d and execution %d out of their intervals", j.Release, j.Execution)
+		}
+		if j != second.Jobs[0] {
+			t.Fatalf("seed %d gives %+v and %+v", seed, j, second.Jobs[0])
+		}
+		// the response time is measured from the earliest arrival time
+		if j.ResponseTime != j.Finish-1 {
+			t.Errorf("response time %d, finish %d", j.ResponseTime, j.Finish)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(WithCores(0)); err == nil {
+		t.Error("no cores should fail")
+	}
+	if _, err := New(WithExecution("average")); err == nil {
+		t.Error("an unknown execution policy should fail")
+	}
+	s, err := New(WithCores(2), WithPartitioned(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Run(common.JobSet{job(0, 0, 0, 1, 10, 1, 2)}); err == nil {
+		t.Error("a job on core 2 of 2 cores should fail")
+	}
+}
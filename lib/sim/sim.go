@@ -0,0 +1,113 @@
+package sim
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// the policies of the simulator
+const (
+	// WCET runs each job for its worst-case execution time, and releases it at its latest arrival time
+	WCET = "wcet"
+	// BCET runs each job for its best-case execution time, and releases it at its earliest arrival time
+	BCET = "bcet"
+	// Uniform draws the execution time and the release of each job uniformly from its interval
+	Uniform = "uniform"
+)
+
+// Simulator schedules the jobs of a job set on identical cores by their job-level priorities, where a smaller value is
+// a higher priority
+type Simulator struct {
+	seed        int64
+	cores       int
+	partitioned bool
+	preemptive  bool
+	execution   string
+	release     string
+}
+
+// Option configures a Simulator
+type Option func(*Simulator)
+
+// WithSeed sets the seed of the sampled execution times and releases. Without this option, a random seed is taken.
+func WithSeed(seed int64) Option {
+	return func(s *Simulator) {
+		s.seed = seed
+	}
+}
+
+// WithCores sets the number of cores
+func WithCores(cores int) Option {
+	return func(s *Simulator) {
+		s.cores = cores
+	}
+}
+
+// WithPartitioned runs each job on the core of its task (PE), instead of on any core (global scheduling)
+func WithPartitioned(partitioned bool) Option {
+	return func(s *Simulator) {
+		s.partitioned = partitioned
+	}
+}
+
+// WithPreemptive lets a higher priority job preempt a running job
+func WithPreemptive(preemptive bool) Option {
+	return func(s *Simulator) {
+		s.preemptive = preemptive
+	}
+}
+
+// WithExecution sets the execution time of each job between its "Cost min" and "Cost max": WCET, BCET, or Uniform
+func WithExecution(execution string) Option {
+	return func(s *Simulator) {
+		s.execution = execution
+	}
+}
+
+// WithRelease sets the release of each job between its "Arrival min" and "Arrival max": WCET (the latest), BCET (the
+// earliest), or Uniform
+func WithRelease(release string) Option {
+	return func(s *Simulator) {
+		s.release = release
+	}
+}
+
+// New creates a simulator with the given options and checks them. By default, the jobs are scheduled preemptively
+// on one core, each for its WCET and released at its latest arrival time.
+func New(options ...Option) (*Simulator, error) {
+	s := &Simulator{
+		seed:       time.Now().UnixNano(),
+		cores:      1,
+		preemptive: true,
+		execution:  WCET,
+		release:    WCET,
+	}
+	for _, option := range options {
+		option(s)
+	}
+
+	if s.cores < 1 {
+		return nil, fmt.Errorf("the number of cores should be positive")
+	}
+	for _, policy := range []string{s.execution, s.release} {
+		if policy != WCET && policy != BCET && policy != Uniform {
+			return nil, fmt.Errorf("invalid sampling policy: %s", policy)
+		}
+	}
+	return s, nil
+}
+
+// sample draws a value of the interval [low, high] with the policy
+func sample(rng *rand.Rand, policy string, low, high int) int {
+	if high <= low {
+		return high
+	}
+	switch policy {
+	case BCET:
+		return low
+	case Uniform:
+		return low + rng.Intn(high-low+1)
+	}
+	return high
+}
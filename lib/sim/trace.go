@@ -0,0 +1,83 @@
+package sim
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"sort"
+	"strconv"
+)
+
+// Interval is a part of the schedule in which a job runs on a core without interruption
+type Interval struct {
+	TaskID int
+	JobID  int
+	Core   int
+	Start  int
+	End    int
+}
+
+// Intervals returns the intervals in which the jobs run, in the order of their start
+func (r Result) Intervals() []Interval {
+	type key struct{ taskID, jobID int }
+	open := make(map[key]int)
+	var intervals []Interval
+	for _, event := range r.Events {
+		k := key{event.TaskID, event.JobID}
+		switch event.Kind {
+		case Start, Resume:
+			open[k] = event.Time
+		case Preempt, Finish:
+			intervals = append(intervals, Interval{TaskID: event.TaskID, JobID: event.JobID, Core: event.Core,
+				Start: open[k], End: event.Time})
+			delete(open, k)
+		}
+	}
+	sort.SliceStable(intervals, func(i, j int) bool {
+		return intervals[i].Start < intervals[j].Start
+	})
+	return intervals
+}
+
+// WriteTrace writes the events of the schedule to a CSV file
+func (r Result) WriteTrace(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	headers := []string{"Time", "Event", "Core", "Task ID", "Job ID"}
+	writer.Write(headers)
+
+	for _, event := range r.Events {
+		row := []string{
+			strconv.Itoa(event.Time),
+			event.Kind,
+			strconv.Itoa(event.Core),
+			strconv.Itoa(event.TaskID),
+			strconv.Itoa(event.JobID),
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteTraceJSON writes the events of the schedule to a JSON file
+func (r Result) WriteTraceJSON(path string) error {
+	data, err := json.MarshalIndent(struct {
+		Cores       int     `json:"cores"`
+		Partitioned bool    `json:"partitioned"`
+		Preemptive  bool    `json:"preemptive"`
+		Events      []Event `json:"events"`
+	}{r.Cores, r.Partitioned, r.Preemptive, r.Events}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
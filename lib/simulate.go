@@ -0,0 +1,212 @@
+package lib
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"task-generator/lib/common"
+	"task-generator/lib/sim"
+)
+
+// simulationIndexFile is the name of the summary of the simulation reports in each job set folder
+const simulationIndexFile = "index.simulation.csv"
+
+// SimulationReport is the schedule of a job set, written next to it. The schedule of each job is left out, and can
+// be written to a trace.
+type SimulationReport struct {
+	File        string           `yaml:"file"`
+	Cores       int              `yaml:"cores"`
+	Partitioned bool             `yaml:"partitioned"`
+	Preemptive  bool             `yaml:"preemptive"`
+	Misses      int              `yaml:"misses"`
+	Schedulable bool             `yaml:"schedulable"`
+	Deadlock    bool             `yaml:"deadlock"`
+	Makespan    int              `yaml:"makespan"`
+	Tasks       []sim.TaskResult `yaml:"tasks"`
+}
+
+// simulationPath returns the path of a file of the simulation of a job set with the given suffix, e.g.,
+// "jobset-uniform_0.sim.yaml" for "jobset-uniform_0.csv"
+func simulationPath(jobSetPath, suffix string) string {
+	return jobSetPath[:strings.LastIndex(jobSetPath, ".")] + "." + suffix
+}
+
+// SimulateJobSets simulates the job set of each task set in the root folder, and writes a report next to the job set.
+// Each job set folder also gets a summary of all the reports in it. The jobs run on the given number of cores, or
+// without it, on the number of cores in the manifest of each task set. For partitioned scheduling, a job runs on the
+// core of its task, or of its vertex for the job sets of DAGs. With trace, the events of the schedule are written to
+// a CSV trace ("jobset-uniform_0.trace.csv"), and the schedule to a Gantt chart ("jobset-uniform_0.gantt.html").
+func SimulateJobSets(root, outputFormat string, cores int, trace bool, options ...sim.Option) error {
+	taskSetPaths, err := findTaskSetPaths(root, outputFormat)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	var dirs []string
+	for _, path := range taskSetPaths {
+		jobPath := jobSetPath(path)
+		if _, err := os.Stat(jobPath); os.IsNotExist(err) {
+			errs = append(errs, fmt.Errorf("%s: no job set (see generate_job_sets)", path))
+			continue
+		}
+		if err := simulateJobSet(path, outputFormat, cores, trace, options); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", jobPath, err))
+		}
+		if dir := filepath.Dir(jobPath); len(dirs) == 0 || dirs[len(dirs)-1] != dir {
+			dirs = append(dirs, dir)
+		}
+	}
+	// the summaries are written once all the sets are simulated
+	for _, dir := range dirs {
+		if err := writeSimulationIndex(dir); err != nil {
+			errs = append(errs, fmt.Errorf("error writing simulation summary of %s: %w", dir, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// simulateJobSet simulates the job set of a task set file and writes its report
+func simulateJobSet(taskSetPath, outputFormat string, cores int, trace bool, options []sim.Option) error {
+	jobPath := jobSetPath(taskSetPath)
+	jobs, err := readJobSet(jobPath, outputFormat)
+	if err != nil {
+		return fmt.Errorf("error reading job set: %w", err)
+	}
+	if err := mapJobs(jobs, taskSetPath, outputFormat); err != nil {
+		return err
+	}
+	if cores == 0 {
+		// the sets of a sweep can have different numbers of cores
+		manifest, err := readManifest(manifestPath(taskSetPath))
+		if err != nil {
+			return fmt.Errorf("unknown number of cores: %w", err)
+		}
+		cores = manifest.Cores
+	}
+
+	simulator, err := sim.New(append(options, sim.WithCores(cores))...)
+	if err != nil {
+		return err
+	}
+	result, err := simulator.Run(jobs)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&SimulationReport{
+		File:        filepath.Base(jobPath),
+		Cores:       result.Cores,
+		Partitioned: result.Partitioned,
+		Preemptive:  result.Preemptive,
+		Misses:      result.Misses,
+		Schedulable: result.Schedulable,
+		Deadlock:    result.Deadlock,
+		Makespan:    result.Makespan,
+		Tasks:       result.Tasks,
+	})
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(simulationPath(jobPath, "sim.yaml"), data, 0644); err != nil {
+		return err
+	}
+	if trace {
+		if err := result.WriteTrace(simulationPath(jobPath, "trace.csv")); err != nil {
+			return err
+		}
+		return result.WriteGantt(simulationPath(jobPath, "gantt.html"), 0, 0)
+	}
+	return nil
+}
+
+// mapJobs sets the core of each job of a job set that is read from a file, which is the core of its task, or of its
+// vertex when the task set has a DAG
+func mapJobs(jobs common.JobSet, taskSetPath, outputFormat string) error {
+	pes := make(map[int]int)
+	if _, err := os.Stat(precPath(taskSetPath, outputFormat)); err == nil {
+		// the jobs of a DAG are numbered by their vertices
+		vertices, err := readVertexSet(precPath(taskSetPath, outputFormat), outputFormat)
+		if err != nil {
+			return fmt.Errorf("error reading precedence graph: %w", err)
+		}
+		for _, vertex := range vertices {
+			pes[vertex.VertexID] = vertex.PE
+		}
+	} else {
+		// the jobs of a task set are numbered by the index of their task
+		tasks, err := readTaskSet(taskSetPath, outputFormat)
+		if err != nil {
+			return fmt.Errorf("error reading task set: %w", err)
+		}
+		for i, task := range tasks {
+			pes[i] = task.PE
+		}
+	}
+	for _, job := range jobs {
+		pe, ok := pes[job.TaskID]
+		if !ok {
+			return fmt.Errorf("job %d belongs to the unknown task %d", job.JobID, job.TaskID)
+		}
+		job.Task.PE = pe
+	}
+	return nil
+}
+
+// writeSimulationIndex collects all the simulation reports in a job set folder and writes them to a summary file
+func writeSimulationIndex(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.sim.yaml"))
+	if err != nil {
+		return err
+	}
+	var reports []*SimulationReport
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var report SimulationReport
+		if err := yaml.Unmarshal(data, &report); err != nil {
+			return fmt.Errorf("cannot read simulation report %s: %v", path, err)
+		}
+		reports = append(reports, &report)
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].File < reports[j].File
+	})
+
+	file, err := os.Create(filepath.Join(dir, simulationIndexFile))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	headers := []string{"File", "Cores", "Partitioned", "Preemptive", "Misses", "Schedulable", "Deadlock", "Makespan"}
+	writer.Write(headers)
+
+	for _, r := range reports {
+		row := []string{
+			r.File,
+			strconv.Itoa(r.Cores),
+			strconv.FormatBool(r.Partitioned),
+			strconv.FormatBool(r.Preemptive),
+			strconv.Itoa(r.Misses),
+			strconv.FormatBool(r.Schedulable),
+			strconv.FormatBool(r.Deadlock),
+			strconv.Itoa(r.Makespan),
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
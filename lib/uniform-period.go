@@ -1,14 +1,15 @@
 package lib
 
 import (
+	"fmt"
 	"math/rand"
 )
 
 // generatePeriodsUniform generates uniformly distributed periods.
-func generatePeriodsUniform(numTasks int, minPeriod, maxPeriod float64) []int {
+func generatePeriodsUniform(rng *rand.Rand, numTasks int, minPeriod, maxPeriod float64) []int {
 	periods := make([]int, numTasks)
 	for i := 0; i < numTasks; i++ {
-		periods[i] = int(rand.Float64()*(maxPeriod-minPeriod) + minPeriod)
+		periods[i] = int(rng.Float64()*(maxPeriod-minPeriod) + minPeriod)
 	}
 
 	return periods
@@ -16,8 +17,8 @@ func generatePeriodsUniform(numTasks int, minPeriod, maxPeriod float64) []int {
 
 // generatePeriodsUniformDiscrete generates uniformly distributed periods and
 // rounds them down to the nearest predefined periods.
-func generatePeriodsUniformDiscrete(numTasks int, minPeriod, maxPeriod float64, roundDownSet []int) []int {
-	periodSet := generatePeriodsUniform(numTasks, minPeriod, maxPeriod)
+func generatePeriodsUniformDiscrete(rng *rand.Rand, numTasks int, minPeriod, maxPeriod float64, roundDownSet []int) []int {
+	periodSet := generatePeriodsUniform(rng, numTasks, minPeriod, maxPeriod)
 
 	roundedPeriodSets := make([]int, len(periodSet))
 	for i, p := range periodSet {
@@ -31,3 +32,36 @@ func generatePeriodsUniformDiscrete(numTasks int, minPeriod, maxPeriod float64,
 
 	return roundedPeriodSets
 }
+
+// uniformPeriodGenerator is the "uniform" and "uniform-discrete" period distribution
+type uniformPeriodGenerator struct {
+	discrete bool
+}
+
+// checkPeriodRange checks the range of the periods and, for the discrete distributions, the predefined periods
+func checkPeriodRange(p PeriodParams, discrete bool) error {
+	if len(p.Range) != 2 {
+		return fmt.Errorf("the period range should be given as [min, max]")
+	}
+	if p.Range[0] <= 0 || p.Range[0] > p.Range[1] {
+		return fmt.Errorf("invalid period range [%d, %d]", p.Range[0], p.Range[1])
+	}
+	if discrete && (len(p.Periods) == 0 || p.Periods[len(p.Periods)-1] > p.Range[0]) {
+		// otherwise some periods cannot be rounded down
+		return fmt.Errorf("the periods should contain a period not larger than %d", p.Range[0])
+	}
+	return nil
+}
+
+// Check checks the period range
+func (u uniformPeriodGenerator) Check(p PeriodParams) error {
+	return checkPeriodRange(p, u.discrete)
+}
+
+// Periods generates uniformly distributed periods
+func (u uniformPeriodGenerator) Periods(rng *rand.Rand, p PeriodParams) ([]int, error) {
+	if u.discrete {
+		return generatePeriodsUniformDiscrete(rng, p.NumTasks, float64(p.Range[0]), float64(p.Range[1]), p.Periods), nil
+	}
+	return generatePeriodsUniform(rng, p.NumTasks, float64(p.Range[0]), float64(p.Range[1])), nil
+}
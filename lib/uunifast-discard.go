@@ -1,13 +1,14 @@
 package lib
 
 import (
+	"fmt"
 	"math"
 	"math/rand"
 )
 
 // uunifastDiscard generates utilization values using the UUniFast algorithm and discard
 // tasks that exceed the utilization limit.
-func uunifastDiscard(numTask int, utilization float64, taskUtilizationLimit float64) []float64 {
+func uunifastDiscard(rng *rand.Rand, numTask int, utilization float64, taskUtilizationLimit float64) []float64 {
 	utilizationValues := make([]float64, numTask)
 	// an infinite loop that will break when the utilization values are within the limit
 	for {
@@ -15,7 +16,7 @@ func uunifastDiscard(numTask int, utilization float64, taskUtilizationLimit floa
 		sumU := utilization
 		for i := 0; i < numTask-1; i++ {
 			//generate a random number between 0 and 1
-			r := float64(rand.Float32())
+			r := float64(rng.Float32())
 			nextSumU := sumU * math.Pow(r, 1.0/float64(numTask-(i+1)))
 			utilizationValues[i] = sumU - nextSumU
 			sumU = nextSumU
@@ -36,3 +37,26 @@ func uunifastDiscard(numTask int, utilization float64, taskUtilizationLimit floa
 	return utilizationValues
 
 }
+
+// uunifastGenerator is the "uunifast" utilization distribution
+type uunifastGenerator struct{}
+
+// Check checks that the utilization can be split into tasks of at most 1.0
+func (uunifastGenerator) Check(p UtilizationParams) error {
+	if err := checkNoTaskBounds(p); err != nil {
+		return err
+	}
+	if len(p.Bounds) > 0 {
+		return fmt.Errorf("the utilization bounds are only valid for rand-fixed-sum and drs utilization distributions")
+	}
+	// with U = n, every task would need a utilization of exactly 1.0, which is never drawn
+	if p.Utilization >= float64(p.NumTasks) {
+		return fmt.Errorf("the utilization %.2f cannot be split into %d tasks", p.Utilization, p.NumTasks)
+	}
+	return nil
+}
+
+// Utilizations generates the utilizations with UUniFast-Discard
+func (uunifastGenerator) Utilizations(rng *rand.Rand, p UtilizationParams) ([]float64, error) {
+	return uunifastDiscard(rng, p.NumTasks, p.Utilization, 1.0), nil
+}
@@ -0,0 +1,24 @@
+package lib
+
+import "testing"
+
+func TestUUniFastGeneratorCheck(t *testing.T) {
+	tests := []struct {
+		name   string
+		params UtilizationParams
+		valid  bool
+	}{
+		{"below the number of tasks", UtilizationParams{NumTasks: 4, Utilization: 3.9}, true},
+		{"number of tasks", UtilizationParams{NumTasks: 4, Utilization: 4}, false},
+		{"above the number of tasks", UtilizationParams{NumTasks: 4, Utilization: 4.5}, false},
+		{"bounds", UtilizationParams{NumTasks: 4, Utilization: 2, Bounds: []float64{0, 0.6}}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := uunifastGenerator{}.Check(test.params)
+			if (err == nil) != test.valid {
+				t.Errorf("Check() = %v, valid: %v", err, test.valid)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"flag"
+	"strconv"
+	"task-generator/lib"
+	"task-generator/lib/sim"
+	"time"
+)
+
+// simulate simulates the job sets in the output folder of a config file, e.g.,
+// "generate simulate -config config.yaml -partitioned -execution uniform"
+func simulate(args []string) {
+	flags := flag.NewFlagSet("simulate", flag.ExitOnError)
+	configFile := flags.String("config", "config.yaml", "path to the YAML config file")
+	cores := flags.Int("cores", 0, "number of cores (default: the number of cores of each task set)")
+	partitioned := flags.Bool("partitioned", false, "run each job on the core of its task or vertex "+
+		"(default: global scheduling)")
+	preemptive := flags.Bool("preemptive", true, "let a higher priority job preempt a running job")
+	execution := flags.String("execution", sim.WCET, "execution time of each job: \"wcet\", \"bcet\", or \"uniform\"")
+	release := flags.String("release", sim.WCET, "release of each job: \"wcet\" (the latest arrival time), "+
+		"\"bcet\" (the earliest), or \"uniform\"")
+	seed := flags.Int64("seed", 0, "seed of the uniform execution times and releases (default: a random seed)")
+	trace := flags.Bool("trace", false, "also write the trace and the Gantt chart of each schedule")
+	flags.Parse(args)
+	config := readConfig(*configFile)
+
+	// like the generator, a run without a seed reports its seed so it can be repeated
+	seedSet := false
+	flags.Visit(func(f *flag.Flag) {
+		seedSet = seedSet || f.Name == "seed"
+	})
+	if !seedSet {
+		*seed = time.Now().UnixNano()
+		logger.LogInfo("No seed is given, using seed " + strconv.FormatInt(*seed, 10))
+	}
+
+	err := lib.SimulateJobSets(config.Path, config.OutputFormat, *cores, *trace,
+		sim.WithPartitioned(*partitioned),
+		sim.WithPreemptive(*preemptive),
+		sim.WithExecution(*execution),
+		sim.WithRelease(*release),
+		sim.WithSeed(*seed))
+	if err != nil {
+		logger.LogFatal("Error simulating job sets: " + err.Error())
+	}
+	logger.LogInfo("Simulation reports are written next to the job sets in " + config.Path)
+}
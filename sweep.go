@@ -0,0 +1,219 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+)
+
+// sweepRange is a range of values given as {from, to, step} in the config file
+type sweepRange struct {
+	From float64 `yaml:"from"`
+	To   float64 `yaml:"to"`
+	Step float64 `yaml:"step"`
+}
+
+// values returns all the values of the range, including both ends
+func (r sweepRange) values() ([]float64, error) {
+	if r.Step <= 0 {
+		return nil, fmt.Errorf("the step of a range should be positive")
+	}
+	if r.To < r.From {
+		return nil, fmt.Errorf("the end of a range should not be smaller than its start")
+	}
+	// a small tolerance, so floating-point errors do not drop the last value
+	n := int(math.Floor((r.To-r.From)/r.Step+1e-9)) + 1
+	values := make([]float64, n)
+	for i := range values {
+		// computing each value from the start avoids accumulating rounding errors
+		values[i] = math.Round((r.From+float64(i)*r.Step)*1e9) / 1e9
+	}
+	return values, nil
+}
+
+// FloatSweep is a parameter that can be given as a single value, a list, or a {from, to, step} range
+type FloatSweep []float64
+
+// UnmarshalYAML reads a float sweep from a single value, a list, or a range
+func (s *FloatSweep) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var single float64
+	if err := unmarshal(&single); err == nil {
+		*s = FloatSweep{single}
+		return nil
+	}
+	var list []float64
+	if err := unmarshal(&list); err == nil {
+		*s = list
+		return nil
+	}
+	var r sweepRange
+	if err := unmarshal(&r); err != nil {
+		return fmt.Errorf("expected a number, a list, or a {from, to, step} range")
+	}
+	values, err := r.values()
+	if err != nil {
+		return err
+	}
+	*s = values
+	return nil
+}
+
+// MarshalYAML writes a sweep with a single value as a plain value
+func (s FloatSweep) MarshalYAML() (interface{}, error) {
+	if len(s) == 1 {
+		return s[0], nil
+	}
+	return []float64(s), nil
+}
+
+// IntSweep is an integer parameter that can be given as a single value, a list, or a {from, to, step} range
+type IntSweep []int
+
+// UnmarshalYAML reads an integer sweep from a single value, a list, or a range
+func (s *IntSweep) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var single int
+	if err := unmarshal(&single); err == nil {
+		*s = IntSweep{single}
+		return nil
+	}
+	var list []int
+	if err := unmarshal(&list); err == nil {
+		*s = list
+		return nil
+	}
+	r := sweepRange{Step: 1}
+	if err := unmarshal(&r); err != nil {
+		return fmt.Errorf("expected an integer, a list, or a {from, to, step} range")
+	}
+	if r.From != math.Trunc(r.From) || r.To != math.Trunc(r.To) || r.Step != math.Trunc(r.Step) {
+		return fmt.Errorf("the range of an integer parameter should only contain integers")
+	}
+	values, err := r.values()
+	if err != nil {
+		return err
+	}
+	*s = make(IntSweep, len(values))
+	for i, v := range values {
+		(*s)[i] = int(v)
+	}
+	return nil
+}
+
+// MarshalYAML writes a sweep with a single value as a plain value
+func (s IntSweep) MarshalYAML() (interface{}, error) {
+	if len(s) == 1 {
+		return s[0], nil
+	}
+	return []int(s), nil
+}
+
+// floatParam and intParam select a swept parameter of a config
+type floatParam func(c *Config) *FloatSweep
+type intParam func(c *Config) *IntSweep
+
+// dagParams returns the DAG parameters that are used by the DAG type of the config
+func (c *Config) dagParams() ([]floatParam, []intParam) {
+	if !c.GenerateDAGs {
+		return nil, nil
+	}
+	forkProb := func(c *Config) *FloatSweep { return &c.ForkProb }
+	edgeProb := func(c *Config) *FloatSweep { return &c.EdgeProb }
+	maxBranch := func(c *Config) *IntSweep { return &c.MaxBranch }
+	maxVertices := func(c *Config) *IntSweep { return &c.MaxVertices }
+	numRoots := func(c *Config) *IntSweep { return &c.NumRoots }
+	maxDepth := func(c *Config) *IntSweep { return &c.MaxDepth }
+
+	switch c.DAGType {
+	case "fork-join":
+		return []floatParam{forkProb, edgeProb}, []intParam{maxBranch, maxVertices, maxDepth}
+	case "random":
+		return nil, []intParam{numRoots, maxBranch, maxDepth}
+	}
+	return nil, nil
+}
+
+// IsDAGSweep reports whether one of the used DAG parameters has more than one value
+func (c *Config) IsDAGSweep() bool {
+	floats, ints := c.dagParams()
+	for _, param := range floats {
+		if len(*param(c)) > 1 {
+			return true
+		}
+	}
+	for _, param := range ints {
+		if len(*param(c)) > 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// Points returns the cartesian product of all the swept parameters, each point as a config with single values
+func (c Config) Points() []Config {
+	floats := []floatParam{
+		func(c *Config) *FloatSweep { return &c.Utilization },
+		func(c *Config) *FloatSweep { return &c.Jitter },
+	}
+	ints := []intParam{
+		func(c *Config) *IntSweep { return &c.NumCores },
+		func(c *Config) *IntSweep { return &c.Tasks },
+	}
+	dagFloats, dagInts := c.dagParams()
+	floats = append(floats, dagFloats...)
+	ints = append(ints, dagInts...)
+
+	points := []Config{c}
+	for _, param := range floats {
+		values := *param(&c)
+		if len(values) == 0 {
+			// a missing parameter keeps its zero value
+			values = FloatSweep{0}
+		}
+		var next []Config
+		for _, p := range points {
+			for _, v := range values {
+				*param(&p) = FloatSweep{v}
+				next = append(next, p)
+			}
+		}
+		points = next
+	}
+	for _, param := range ints {
+		values := *param(&c)
+		if len(values) == 0 {
+			// a missing parameter keeps its zero value
+			values = IntSweep{0}
+		}
+		var next []Config
+		for _, p := range points {
+			for _, v := range values {
+				*param(&p) = IntSweep{v}
+				next = append(next, p)
+			}
+		}
+		points = next
+	}
+	return points
+}
+
+// Root returns the output folder of a sweep point. When the DAG parameters are swept, each DAG point gets its own
+// folder, so the DAGs of different points do not overwrite each other.
+func (c Config) Root(dagSweep bool) string {
+	if !dagSweep {
+		return c.Path
+	}
+	path := filepath.Join(c.Path, fmt.Sprintf("%s-dag", c.DAGType))
+	switch c.DAGType {
+	case "fork-join":
+		path = filepath.Join(path, fmt.Sprintf("%.2f-forkProb", c.ForkProb[0]))
+		path = filepath.Join(path, fmt.Sprintf("%.2f-edgeProb", c.EdgeProb[0]))
+		path = filepath.Join(path, fmt.Sprintf("%d-branches", c.MaxBranch[0]))
+		path = filepath.Join(path, fmt.Sprintf("%d-vertices", c.MaxVertices[0]))
+		path = filepath.Join(path, fmt.Sprintf("%d-depth", c.MaxDepth[0]))
+	case "random":
+		path = filepath.Join(path, fmt.Sprintf("%d-roots", c.NumRoots[0]))
+		path = filepath.Join(path, fmt.Sprintf("%d-branches", c.MaxBranch[0]))
+		path = filepath.Join(path, fmt.Sprintf("%d-depth", c.MaxDepth[0]))
+	}
+	return path
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"gopkg.in/yaml.v2"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSweepUnmarshal(t *testing.T) {
+	tests := []struct {
+		name        string
+		yaml        string
+		utilization FloatSweep
+		tasks       IntSweep
+		valid       bool
+	}{
+		{"single values", "utilization: 0.8\ntasks: 5", FloatSweep{0.8}, IntSweep{5}, true},
+		{"lists", "utilization: [0.5, 1.5]\ntasks: [4, 8, 16]", FloatSweep{0.5, 1.5}, IntSweep{4, 8, 16}, true},
+		// the last value is kept despite the rounding errors of the steps
+		{"ranges", "utilization: {from: 0.1, to: 0.7, step: 0.1}\ntasks: {from: 2, to: 10, step: 4}",
+			FloatSweep{0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7}, IntSweep{2, 6, 10}, true},
+		{"range without step", "tasks: {from: 3, to: 5}", nil, IntSweep{3, 4, 5}, true},
+		{"negative step", "utilization: {from: 1, to: 2, step: -0.5}", nil, nil, false},
+		{"reversed range", "tasks: {from: 5, to: 3, step: 1}", nil, nil, false},
+		{"fractional task range", "tasks: {from: 1, to: 3, step: 0.5}", nil, nil, false},
+		{"invalid value", "tasks: many", nil, nil, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var config Config
+			err := yaml.Unmarshal([]byte(test.yaml), &config)
+			if (err == nil) != test.valid {
+				t.Fatalf("Unmarshal() = %v, valid: %v", err, test.valid)
+			}
+			if !test.valid {
+				return
+			}
+			if !reflect.DeepEqual(config.Utilization, test.utilization) {
+				t.Errorf("utilization %v, want %v", config.Utilization, test.utilization)
+			}
+			if !reflect.DeepEqual(config.Tasks, test.tasks) {
+				t.Errorf("tasks %v, want %v", config.Tasks, test.tasks)
+			}
+		})
+	}
+}
+
+func TestSweepMarshal(t *testing.T) {
+	// a point is written back with plain values
+	data, err := yaml.Marshal(struct {
+		Single FloatSweep `yaml:"single"`
+		List   IntSweep   `yaml:"list"`
+	}{FloatSweep{0.5}, IntSweep{1, 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "single: 0.5\nlist:\n- 1\n- 2\n"; string(data) != want {
+		t.Errorf("written %q, want %q", data, want)
+	}
+}
+
+func TestPoints(t *testing.T) {
+	config := Config{
+		Path:        "out",
+		Utilization: FloatSweep{0.5, 1},
+		Jitter:      FloatSweep{0.1},
+		NumCores:    IntSweep{2, 4},
+		Tasks:       IntSweep{5, 10, 20},
+		DAGType:     "fork-join",
+		ForkProb:    FloatSweep{0.2, 0.8},
+		EdgeProb:    FloatSweep{0.5},
+		MaxBranch:   IntSweep{3},
+		MaxVertices: IntSweep{10},
+		MaxDepth:    IntSweep{3},
+	}
+
+	// without DAGs, the DAG parameters are not swept
+	points := config.Points()
+	if len(points) != 12 || config.IsDAGSweep() {
+		t.Fatalf("%d points instead of 12, DAG sweep: %v", len(points), config.IsDAGSweep())
+	}
+	seen := make(map[[3]float64]bool)
+	for _, p := range points {
+		if len(p.Utilization) != 1 || len(p.Jitter) != 1 || len(p.NumCores) != 1 || len(p.Tasks) != 1 {
+			t.Fatalf("the point %+v has more than one value of a parameter", p)
+		}
+		seen[[3]float64{p.Utilization[0], float64(p.NumCores[0]), float64(p.Tasks[0])}] = true
+		if p.Root(false) != "out" {
+			t.Errorf("the root of a point is %s instead of out", p.Root(false))
+		}
+	}
+	if len(seen) != 12 {
+		t.Errorf("%d different points instead of 12", len(seen))
+	}
+
+	// with fork-join DAGs, each DAG point gets its own folder
+	config.GenerateDAGs = true
+	points = config.Points()
+	if len(points) != 24 || !config.IsDAGSweep() {
+		t.Fatalf("%d points instead of 24, DAG sweep: %v", len(points), config.IsDAGSweep())
+	}
+	roots := make(map[string]bool)
+	for _, p := range points {
+		roots[p.Root(true)] = true
+	}
+	want := filepath.Join("out", "fork-join-dag", "0.20-forkProb", "0.50-edgeProb", "3-branches", "10-vertices",
+		"3-depth")
+	if len(roots) != 2 || !roots[want] {
+		t.Errorf("the roots of the DAG points are %v", roots)
+	}
+
+	// the random DAGs have no fork probability
+	config.DAGType = "random"
+	config.NumRoots = IntSweep{1}
+	if config.IsDAGSweep() || len(config.Points()) != 12 {
+		t.Errorf("the fork probability is swept for random DAGs")
+	}
+}